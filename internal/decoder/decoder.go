@@ -0,0 +1,251 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const windowSize = 64 * 1024
+
+// Decoder reads little endian values from a byte slice cursor without reflection.
+// A decoder created from an io.Reader uses the slice as a window that is refilled
+// from the reader as it is consumed. Read errors panic, like readfields.ReadFields.
+type Decoder struct {
+	r         io.Reader
+	buf       []byte
+	pos       int
+	base      int64 // stream position of buf[0]
+	interner  *Interner
+	encodings saveformat.StringEncodings
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, buf: make([]byte, 0, windowSize)}
+}
+
+func NewBytesDecoder(data []byte) *Decoder {
+	return &Decoder{buf: data}
+}
+
+// SetInterner makes InternedString deduplicate the strings it returns using in.
+func (d *Decoder) SetInterner(in *Interner) {
+	d.interner = in
+}
+
+func (d *Decoder) Interner() *Interner {
+	return d.interner
+}
+
+// SetStringEncodings makes the decoder record the strings read in another encoding than the default in e.
+func (d *Decoder) SetStringEncodings(e saveformat.StringEncodings) {
+	d.encodings = e
+}
+
+func (d *Decoder) StringEncodings() saveformat.StringEncodings {
+	return d.encodings
+}
+
+func (d *Decoder) Position() int64 {
+	return d.base + int64(d.pos)
+}
+
+// fill makes sure at least n bytes are available at the cursor.
+func (d *Decoder) fill(n int) {
+	remaining := len(d.buf) - d.pos
+	if remaining >= n {
+		return
+	}
+	if d.r == nil {
+		panic(fmt.Errorf("reading %d bytes at %d: %w", n, d.Position(), io.ErrUnexpectedEOF))
+	}
+
+	d.base += int64(d.pos)
+	if n > cap(d.buf) {
+		buf := make([]byte, remaining, max(n, windowSize))
+		copy(buf, d.buf[d.pos:])
+		d.buf = buf
+	} else {
+		copy(d.buf, d.buf[d.pos:])
+		d.buf = d.buf[:remaining]
+	}
+	d.pos = 0
+
+	read, err := io.ReadAtLeast(d.r, d.buf[remaining:cap(d.buf)], n-remaining)
+	d.buf = d.buf[:remaining+read]
+	if err != nil {
+		panic(fmt.Errorf("reading %d bytes at %d: %w", n, d.Position(), err))
+	}
+}
+
+// next returns the next n bytes, only valid until the next call on the decoder.
+func (d *Decoder) next(n int) []byte {
+	d.fill(n)
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *Decoder) Read(p []byte) (int, error) {
+	if d.pos == len(d.buf) {
+		if d.r == nil {
+			return 0, io.EOF
+		}
+		d.base += int64(d.pos)
+		d.pos = 0
+		n, err := d.r.Read(d.buf[:cap(d.buf)])
+		d.buf = d.buf[:n]
+		if n == 0 {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buf[d.pos:])
+	d.pos += n
+	return n, nil
+}
+
+func (d *Decoder) Byte() byte {
+	return d.next(1)[0]
+}
+
+func (d *Decoder) Int8() int8 {
+	return int8(d.Byte())
+}
+
+func (d *Decoder) Uint32() uint32 {
+	return binary.LittleEndian.Uint32(d.next(4))
+}
+
+func (d *Decoder) Int32() int32 {
+	return int32(d.Uint32())
+}
+
+func (d *Decoder) Uint64() uint64 {
+	return binary.LittleEndian.Uint64(d.next(8))
+}
+
+func (d *Decoder) Int64() int64 {
+	return int64(d.Uint64())
+}
+
+func (d *Decoder) Float32() float32 {
+	return math.Float32frombits(d.Uint32())
+}
+
+func (d *Decoder) Float64() float64 {
+	return math.Float64frombits(d.Uint64())
+}
+
+// Bytes returns a copy of the next n bytes.
+func (d *Decoder) Bytes(n int) []byte {
+	b := make([]byte, n)
+	copy(b, d.next(n))
+	return b
+}
+
+// ReadInto fills b with the next len(b) bytes.
+func (d *Decoder) ReadInto(b []byte) {
+	copy(b, d.next(len(b)))
+}
+
+func (d *Decoder) Skip(n int64) {
+	for n > 0 {
+		step := int(min(n, windowSize))
+		d.next(step)
+		n -= int64(step)
+	}
+}
+
+// String reads a length prefixed string, UTF-8 for a positive length and UTF-16 LE for a negative one.
+func (d *Decoder) String() string {
+	return d.readString(false)
+}
+
+// InternedString reads a string that is part of the save vocabulary, like a type path or
+// property name, and interns it when the decoder has an interner. Strings unique to one
+// object, like path names, should be read with String.
+func (d *Decoder) InternedString() string {
+	return d.readString(d.interner != nil)
+}
+
+func (d *Decoder) readString(intern bool) string {
+	length := int(d.Int32())
+	if length == 0 {
+		return ""
+	}
+
+	if length > 0 {
+		data := d.next(length)
+
+		// Strip null terminator if present
+		if data[length-1] == 0 {
+			data = data[:length-1]
+		}
+
+		if !utf8.Valid(data) {
+			panic(fmt.Sprintf("Error reading string field: invalid UTF-8 string len: %v", length))
+		}
+
+		var s string
+		if intern && len(data) <= maxInternLength {
+			s = d.interner.intern(data)
+		} else {
+			s = string(data)
+		}
+		if d.encodings != nil && !isASCII(s) {
+			d.encodings[s] = saveformat.EncodingUTF8
+		}
+		return s
+	}
+
+	charCount := -length
+	data := d.next(charCount * 2)
+
+	utf16Data := make([]uint16, charCount)
+	for i := range utf16Data {
+		utf16Data[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	// Remove null terminator if present
+	if utf16Data[len(utf16Data)-1] == 0 {
+		utf16Data = utf16Data[:len(utf16Data)-1]
+	}
+
+	s := string(utf16.Decode(utf16Data))
+	if d.encodings != nil && isASCII(s) {
+		d.encodings[s] = saveformat.EncodingUTF16
+	}
+	return s
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func ReadAndKeep(d *Decoder, read func() uint32) []byte {
+	startPos := d.Position()
+	objectSize := read()
+	endPos := d.Position()
+
+	bytesRead := uint32(endPos - startPos)
+	if bytesRead > objectSize {
+		panic("Read more bytes than expected")
+	}
+
+	trailingBytes := objectSize - bytesRead
+	if trailingBytes == 0 {
+		return nil
+	}
+	return d.Bytes(int(trailingBytes))
+}
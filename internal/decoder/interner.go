@@ -0,0 +1,24 @@
+package decoder
+
+// strings longer than this are mostly free text and are not worth interning
+const maxInternLength = 256
+
+// Interner deduplicates the vocabulary strings of a save, like type paths, property names and
+// level names, so their many copies share memory. It is not safe for concurrent use, every
+// goroutine decoding a part of the save uses its own interner.
+type Interner struct {
+	strings map[string]string
+}
+
+func NewInterner() *Interner {
+	return &Interner{strings: make(map[string]string)}
+}
+
+func (in *Interner) intern(b []byte) string {
+	if s, ok := in.strings[string(b)]; ok {
+		return s
+	}
+	s := string(b)
+	in.strings[s] = s
+	return s
+}
@@ -4,15 +4,19 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/Maurits825/satisfactory-savefile-parser/internal/countingreader"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
 	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
-func readSaveFileBody(cr *countingreader.CountingReader, version uint32) *saveformat.SaveFileBody {
-	var body saveformat.SaveFileBody
+// levelsReader reads the sub levels followed by the persistent level.
+type levelsReader func(d *decoder.Decoder, subLevelCount uint32, version uint32) []saveformat.LevelData
 
-	readfields.ReadFields(cr,
+func readSaveFileBody(d *decoder.Decoder, version uint32, readLevels levelsReader) *saveformat.SaveFileBody {
+	body := saveformat.SaveFileBody{StringEncodings: make(saveformat.StringEncodings)}
+	d.SetStringEncodings(body.StringEncodings)
+
+	readfields.ReadFields(d,
 		&body.UncompressedSize, &body.Value6, &body.NoneString1, &body.Value0,
 		&body.Unknown1, &body.Value1, &body.NoneString2, &body.Unknown2,
 	)
@@ -22,26 +26,18 @@ func readSaveFileBody(cr *countingreader.CountingReader, version uint32) *savefo
 	}
 
 	for range 5 {
-		grid := readLevelGroupingGrid(cr)
+		grid := readLevelGroupingGrid(d)
 		body.LevelGroupingGrids = append(body.LevelGroupingGrids, *grid)
 	}
 
-	readfields.ReadFields(cr, &body.SubLevelCount)
-
-	for range body.SubLevelCount {
-		levelData := readLevelData(cr, version, false)
-		body.Levels = append(body.Levels, *levelData)
-	}
-
-	fmt.Println("Reading persistent level data ...")
-	levelData := readLevelData(cr, version, true)
-	body.Levels = append(body.Levels, *levelData)
+	readfields.ReadFields(d, &body.SubLevelCount)
+	body.Levels = readLevels(d, body.SubLevelCount, version)
 
 	//TODO zero field present here?
 
-	readReferenceList(cr, &body)
+	readReferenceList(d, &body)
 
-	leftBytes, err := io.ReadAll(cr)
+	leftBytes, err := io.ReadAll(d)
 	if err != nil {
 		panic("Reading left bytes after reading body: " + err.Error())
 	}
@@ -52,6 +48,20 @@ func readSaveFileBody(cr *countingreader.CountingReader, version uint32) *savefo
 	return &body
 }
 
+func sequentialLevelsReader(opts Options) levelsReader {
+	return func(d *decoder.Decoder, subLevelCount uint32, version uint32) []saveformat.LevelData {
+		levels := make([]saveformat.LevelData, 0, subLevelCount+1)
+		for range subLevelCount {
+			levelData := readLevelData(d, version, false, &levelCollector{}, opts)
+			levels = append(levels, *levelData)
+		}
+
+		fmt.Println("Reading persistent level data ...")
+		levelData := readLevelData(d, version, true, &levelCollector{}, opts)
+		return append(levels, *levelData)
+	}
+}
+
 func readLevelGroupingGrid(r io.Reader) *saveformat.LevelGroupingGrid {
 	var grid saveformat.LevelGroupingGrid
 
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"compress/zlib"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
+	"sync"
 )
 
 type CompressedSaveFileBody struct {
@@ -29,28 +32,140 @@ func (body *CompressedSaveFileBody) isValid() bool {
 		body.UncompressedSize1 == body.UncompressedSize2
 }
 
-func readCompressedSaveFileBody(file io.Reader) (io.ReadCloser, uint64) {
+// readCompressedChunk reads the next chunk header and its compressed bytes, returns io.EOF after the last chunk.
+func readCompressedChunk(file io.Reader) (*CompressedSaveFileBody, []byte, error) {
 	var compressedBody CompressedSaveFileBody
 	if err := binary.Read(file, binary.LittleEndian, &compressedBody); err != nil {
-		if err == io.EOF {
-			return nil, 0
-		}
-		panic(err)
+		return nil, nil, err
 	}
 	if !compressedBody.isValid() {
-		panic("invalid compressed save file body")
+		return nil, nil, errors.New("invalid compressed save file body")
 	}
 
 	compressedBytes := make([]byte, compressedBody.CompressedSize1)
 	if _, err := io.ReadFull(file, compressedBytes); err != nil {
-		panic("reading compressed body: " + err.Error())
+		return nil, nil, fmt.Errorf("reading compressed body: %w", err)
 	}
 
-	b := bytes.NewReader(compressedBytes)
-	zr, err := zlib.NewReader(b)
-	if err != nil {
-		panic("creating zlib reader: " + err.Error())
+	return &compressedBody, compressedBytes, nil
+}
+
+type chunk struct {
+	data []byte
+	err  error
+}
+
+type chunkJob struct {
+	compressed       []byte
+	uncompressedSize uint64
+	result           chan chunk
+}
+
+// chunkReader inflates the compressed chunks of the body on a pool of workers
+// and returns the data in the original order. At most maxPending chunks are
+// kept in memory ahead of the reader.
+type chunkReader struct {
+	pending   chan chan chunk
+	current   []byte
+	err       error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newChunkReader(file io.Reader, workers int, maxPending int) *chunkReader {
+	r := &chunkReader{
+		pending: make(chan chan chunk, maxPending),
+		done:    make(chan struct{}),
 	}
 
-	return zr, compressedBody.UncompressedSize1
+	jobs := make(chan chunkJob)
+	go r.readChunks(file, jobs)
+	for range workers {
+		go inflateChunks(jobs)
+	}
+
+	return r
+}
+
+func (r *chunkReader) readChunks(file io.Reader, jobs chan<- chunkJob) {
+	defer close(r.pending)
+	defer close(jobs)
+
+	for {
+		header, compressed, err := readCompressedChunk(file)
+		if err == io.EOF {
+			return
+		}
+
+		result := make(chan chunk, 1)
+		select {
+		case r.pending <- result:
+		case <-r.done:
+			return
+		}
+
+		if err != nil {
+			result <- chunk{err: err}
+			return
+		}
+
+		select {
+		case jobs <- chunkJob{compressed: compressed, uncompressedSize: header.UncompressedSize1, result: result}:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func inflateChunks(jobs <-chan chunkJob) {
+	var zr io.ReadCloser
+	for job := range jobs {
+		var err error
+		if zr == nil {
+			zr, err = zlib.NewReader(bytes.NewReader(job.compressed))
+		} else {
+			err = zr.(zlib.Resetter).Reset(bytes.NewReader(job.compressed), nil)
+		}
+		if err != nil {
+			job.result <- chunk{err: fmt.Errorf("creating zlib reader: %w", err)}
+			continue
+		}
+
+		data := make([]byte, job.uncompressedSize)
+		if _, err := io.ReadFull(zr, data); err != nil {
+			job.result <- chunk{err: fmt.Errorf("decompressing chunk: %w", err)}
+			continue
+		}
+		job.result <- chunk{data: data}
+	}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.current) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		result, ok := <-r.pending
+		if !ok {
+			r.err = io.EOF
+			continue
+		}
+		select {
+		case c := <-result:
+			r.current, r.err = c.data, c.err
+		case <-r.done:
+			r.err = errors.New("reading from closed chunk reader")
+		}
+	}
+
+	n := copy(p, r.current)
+	r.current = r.current[n:]
+	return n, nil
+}
+
+// Close stops the decompression workers, it is safe to call before all data is read.
+func (r *chunkReader) Close() error {
+	r.closeOnce.Do(func() { close(r.done) })
+	return nil
 }
@@ -0,0 +1,135 @@
+package readsave
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"runtime"
+	"sync"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// Decoding levels in parallel only pays off when there are enough levels and bytes to spread
+// over more than one CPU, smaller bodies are decoded sequentially. A 2 MB body with 298 mostly
+// empty levels decodes slower in parallel.
+var (
+	MinParallelLevels       = 2
+	MinParallelBytes  int64 = 16 << 20
+)
+
+// parallelLevelsReader decodes the levels of an in-memory body on separate goroutines.
+// data must be the full body that d is reading from.
+func parallelLevelsReader(data []byte, opts Options) levelsReader {
+	return func(d *decoder.Decoder, subLevelCount uint32, version uint32) []saveformat.LevelData {
+		start := d.Position()
+		levelBytes, size := indexLevels(data[start:], subLevelCount, version)
+		if len(levelBytes) < MinParallelLevels || size < MinParallelBytes || runtime.GOMAXPROCS(0) < 2 {
+			return sequentialLevelsReader(opts)(d, subLevelCount, version)
+		}
+
+		levels := make([]saveformat.LevelData, len(levelBytes))
+		encodings := make([]saveformat.StringEncodings, len(levelBytes))
+		panics := make([]any, len(levelBytes))
+		sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+		var wg sync.WaitGroup
+		for i, b := range levelBytes {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					panics[i] = recover()
+					<-sem
+					wg.Done()
+				}()
+				isPersistentLevel := i == len(levelBytes)-1
+				levelDecoder := decoder.NewBytesDecoder(b)
+				if d.Interner() != nil {
+					levelDecoder.SetInterner(decoder.NewInterner())
+				}
+				if d.StringEncodings() != nil {
+					encodings[i] = make(saveformat.StringEncodings)
+					levelDecoder.SetStringEncodings(encodings[i])
+				}
+				levels[i] = *readLevelData(levelDecoder, version, isPersistentLevel, &levelCollector{}, opts)
+			}()
+		}
+		wg.Wait()
+
+		for i, p := range panics {
+			if p != nil {
+				panic(fmt.Sprintf("Reading level %d: %v", i, p))
+			}
+		}
+		for _, e := range encodings {
+			maps.Copy(d.StringEncodings(), e)
+		}
+
+		d.Skip(size)
+		return levels
+	}
+}
+
+// levelIndex walks the level layout using the size fields without decoding any objects.
+type levelIndex struct {
+	data []byte
+	pos  int64
+}
+
+func (idx *levelIndex) skip(n int64) {
+	if n < 0 || idx.pos+n > int64(len(idx.data)) {
+		panic(fmt.Sprintf("Level index out of range at %d", idx.pos))
+	}
+	idx.pos += n
+}
+
+func (idx *levelIndex) uint32() uint32 {
+	start := idx.pos
+	idx.skip(4)
+	return binary.LittleEndian.Uint32(idx.data[start:])
+}
+
+func (idx *levelIndex) uint64() uint64 {
+	start := idx.pos
+	idx.skip(8)
+	return binary.LittleEndian.Uint64(idx.data[start:])
+}
+
+func (idx *levelIndex) skipString() {
+	length := int64(int32(idx.uint32()))
+	if length < 0 {
+		length = -length * 2
+	}
+	idx.skip(length)
+}
+
+// indexLevels returns the bytes of each sub level followed by the persistent level,
+// and the total size of all levels.
+func indexLevels(data []byte, subLevelCount uint32, version uint32) ([][]byte, int64) {
+	idx := &levelIndex{data: data}
+	levels := make([][]byte, 0, subLevelCount+1)
+
+	for i := range subLevelCount + 1 {
+		isPersistentLevel := i == subLevelCount
+		start := idx.pos
+
+		if !isPersistentLevel {
+			idx.skipString()
+		}
+		idx.skip(int64(idx.uint64()))
+		idx.skip(int64(idx.uint64()))
+		if !isPersistentLevel && version >= 51 {
+			idx.skip(4)
+		}
+		collectableCount := idx.uint32()
+		for range collectableCount {
+			idx.skipString()
+			idx.skipString()
+		}
+
+		levels = append(levels, data[start:idx.pos])
+	}
+
+	return levels, idx.pos
+}
@@ -1,10 +1,9 @@
 package readfields
 
 import (
-	"encoding/binary"
-	"io"
+	"fmt"
 
-	"github.com/Maurits825/satisfactory-savefile-parser/internal/countingreader"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
@@ -44,21 +43,25 @@ type GenericProperty[T any] struct {
 	Value   T
 }
 
-func readGenericProperty[T any](r io.Reader) T {
-	var p GenericProperty[T]
-	ReadFields(r, &p.Size, &p.Index, &p.Padding, &p.Value)
-	return p.Value
+func readGenericProperty[T any](readValue func(*decoder.Decoder) T) func(*decoder.Decoder, *saveformat.Property) {
+	return func(d *decoder.Decoder, prop *saveformat.Property) {
+		var p GenericProperty[T]
+		p.Size, p.Index, p.Padding = d.Uint32(), d.Uint32(), d.Byte()
+		p.Value = readValue(d)
+		prop.Index = p.Index
+		prop.Value = p.Value
+	}
 }
 
-var genericPropertyReaders = map[string]func(io.Reader) any{
-	"IntProperty":    func(r io.Reader) any { return readGenericProperty[int32](r) },
-	"FloatProperty":  func(r io.Reader) any { return readGenericProperty[float32](r) },
-	"DoubleProperty": func(r io.Reader) any { return readGenericProperty[float64](r) },
-	"Int8Property":   func(r io.Reader) any { return readGenericProperty[int8](r) },
-	"Int64Property":  func(r io.Reader) any { return readGenericProperty[int64](r) },
-	"UInt32Property": func(r io.Reader) any { return readGenericProperty[uint32](r) },
-	"StrProperty":    func(r io.Reader) any { return readGenericProperty[string](r) },
-	"NameProperty":   func(r io.Reader) any { return readGenericProperty[string](r) },
+var genericPropertyReaders = map[string]func(*decoder.Decoder, *saveformat.Property){
+	"IntProperty":    readGenericProperty((*decoder.Decoder).Int32),
+	"FloatProperty":  readGenericProperty((*decoder.Decoder).Float32),
+	"DoubleProperty": readGenericProperty((*decoder.Decoder).Float64),
+	"Int8Property":   readGenericProperty((*decoder.Decoder).Int8),
+	"Int64Property":  readGenericProperty((*decoder.Decoder).Int64),
+	"UInt32Property": readGenericProperty((*decoder.Decoder).Uint32),
+	"StrProperty":    readGenericProperty((*decoder.Decoder).String),
+	"NameProperty":   readGenericProperty((*decoder.Decoder).String),
 }
 
 type ObjectProperty struct {
@@ -102,13 +105,12 @@ type SetProperty struct {
 }
 
 type StructProperty struct {
-	Size     uint32
-	Index    uint32
-	Type     string
-	Padding1 int64
-	Padding2 int64
-	Padding3 byte
-	Value    any
+	Size    uint32
+	Index   uint32
+	Type    string
+	GUID    Guid
+	Padding byte
+	Value   any
 }
 
 type ArrayProperty struct {
@@ -125,6 +127,8 @@ type ArraySoftObjectProperty struct {
 	Value     uint32
 }
 
+func (p ArraySoftObjectProperty) ObjectReference() saveformat.ObjectReference { return p.Reference }
+
 type Box struct {
 	MinX    float64
 	MinY    float64
@@ -159,12 +163,16 @@ type RailroadTrackPosition struct {
 	Forward   float32
 }
 
+func (p RailroadTrackPosition) ObjectReference() saveformat.ObjectReference { return p.ObjectRef }
+
 type Vector struct {
 	X float64
 	Y float64
 	Z float64
 }
 
+type Guid [16]byte
+
 type DateTime struct {
 	Timestamp int64
 }
@@ -181,214 +189,236 @@ type Identity struct {
 	Data     []byte
 }
 
-func ReadAllProperties(cr *countingreader.CountingReader, props *[]saveformat.Property) {
+func ReadAllProperties(d *decoder.Decoder, props *[]saveformat.Property) {
 	for {
 		var p saveformat.Property
-		ReadFields(cr, &p.Name)
+		p.Name = d.InternedString()
 		if p.Name == "None" {
 			*props = append(*props, p)
 			return
 		} else if p.Name == "" {
 			//there can be a buggy byte on InventoryItem...
-			ReadFields(cr, &p.Name)
+			p.Name = d.InternedString()
 		}
 
-		ReadFields(cr, &p.Type)
-		p.Value = readPropertyData(cr, p.Type)
+		p.Type = d.InternedString()
+		readPropertyData(d, &p)
 		*props = append(*props, p)
 	}
 }
 
-func eatPropertyData(r io.Reader, pSize uint32) {
-	if _, err := io.CopyN(io.Discard, r, int64(pSize)); err != nil {
-		panic("Error reading property data: " + err.Error())
+// DecodeRawProperties decodes a property block that was kept raw, the bytes after
+// the properties are returned as trailing bytes. Each block has its own interner, so
+// blocks can be decoded concurrently.
+func DecodeRawProperties(data []byte) (props []saveformat.Property, trailing []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoding properties: %v", r)
+		}
+	}()
+
+	d := decoder.NewBytesDecoder(data)
+	d.SetInterner(decoder.NewInterner())
+	ReadAllProperties(d, &props)
+
+	if rest := len(data) - int(d.Position()); rest > 0 {
+		trailing = d.Bytes(rest)
 	}
+	return props, trailing, nil
 }
 
-func readPropertyData(cr *countingreader.CountingReader, propertyType string) any {
-	if genericReader, ok := genericPropertyReaders[propertyType]; ok {
-		return genericReader(cr)
+func readPropertyHeader(d *decoder.Decoder) PropertyHeader {
+	return PropertyHeader{Size: d.Uint32(), Index: d.Uint32(), Padding: d.Byte()}
+}
+
+func readPropertyData(d *decoder.Decoder, prop *saveformat.Property) {
+	if genericReader, ok := genericPropertyReaders[prop.Type]; ok {
+		genericReader(d, prop)
+		return
 	}
 
 	//TODO return p? instead of p.value, so we get all the data?
-	switch propertyType {
+	switch prop.Type {
 	case "BoolProperty":
 		var p BoolProperty
-		ReadFields(cr, &p.Padding1, &p.Index, &p.Value, &p.Padding2)
-		return p.Value
+		p.Padding1, p.Index, p.Value, p.Padding2 = d.Uint32(), d.Uint32(), d.Byte(), d.Byte()
+		prop.Index = p.Index
+		prop.Value = p.Value
 	case "ByteProperty":
 		var p ByteProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Type, &p.Padding)
+		p.Size, p.Index, p.Type, p.Padding = d.Uint32(), d.Uint32(), d.InternedString(), d.Byte()
 		if p.Type == "None" {
-			var b byte
-			ReadFields(cr, &b)
-			p.Value = b
+			p.Value = d.Byte()
 		} else {
-			var s string
-			ReadFields(cr, &s)
-			p.Value = s
+			p.Value = d.InternedString()
 		}
-		return p.Value
+		prop.Index, prop.InnerType = p.Index, p.Type
+		prop.Value = p.Value
 	case "ObjectProperty":
 		var p ObjectProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Padding, &p.Value)
-		return p.Value
+		p.PropertyHeader = readPropertyHeader(d)
+		p.Value = ReadObjectReference(d)
+		prop.Index = p.Index
+		prop.Value = p.Value
 	case "SoftObjectProperty":
 		var p SoftObjectProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Padding, &p.ObjectReferenceValue, &p.Value)
-		return p.Value
+		p.PropertyHeader = readPropertyHeader(d)
+		p.ObjectReferenceValue, p.Value = ReadObjectReference(d), d.Uint32()
+		prop.Index = p.Index
+		prop.Value = ArraySoftObjectProperty{Reference: p.ObjectReferenceValue, Value: p.Value}
 	case "SetProperty":
 		var p SetProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Type, &p.Padding1)
-		eatPropertyData(cr, p.Size)
-		return nil
+		p.Size, p.Index, p.Type, p.Padding1 = d.Uint32(), d.Uint32(), d.InternedString(), d.Byte()
+		prop.Index, prop.InnerType = p.Index, p.Type
+		prop.Raw = d.Bytes(int(p.Size))
 	case "StructProperty":
 		var p StructProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Type, &p.Padding1, &p.Padding2, &p.Padding3)
-		p.Value = readTypedData(cr, p.Type)
-		return p.Value
+		p.Size, p.Index, p.Type = d.Uint32(), d.Uint32(), d.InternedString()
+		d.ReadInto(p.GUID[:])
+		p.Padding = d.Byte()
+		p.Value = readTypedData(d, p.Type)
+		prop.Index, prop.InnerType, prop.GUID = p.Index, p.Type, p.GUID
+		prop.Value = p.Value
 	case "ArrayProperty":
-		return readArrayProperty(cr)
+		readArrayProperty(d, prop)
 	case "EnumProperty":
 		var p EnumProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.Type, &p.Padding)
-		eatPropertyData(cr, p.Size)
-		return nil
+		p.Size, p.Index, p.Type, p.Padding, p.Value = d.Uint32(), d.Uint32(), d.InternedString(), d.Byte(), d.InternedString()
+		prop.Index, prop.InnerType = p.Index, p.Type
+		prop.Value = p.Value
 	case "MapProperty":
 		var p MapProperty
-		ReadFields(cr, &p.Size, &p.Index, &p.KeyType, &p.ValueType, &p.Padding)
-		eatPropertyData(cr, p.Size)
-		return nil
+		p.Size, p.Index, p.KeyType, p.ValueType, p.Padding = d.Uint32(), d.Uint32(), d.InternedString(), d.InternedString(), d.Byte()
+		prop.Index, prop.InnerType, prop.ValueType = p.Index, p.KeyType, p.ValueType
+		prop.Raw = d.Bytes(int(p.Size))
 	case "TextProperty":
-		var pSize uint32
-		ReadFields(cr, &pSize)
-		eatPropertyData(cr, pSize+5)
-		return nil
+		var p TextProperty
+		p.PropertyHeader = readPropertyHeader(d)
+		prop.Index = p.Index
+		prop.Raw = d.Bytes(int(p.Size))
 	default:
-		panic("not implemented property type: " + propertyType)
+		panic("not implemented property type: " + prop.Type)
 	}
 }
 
-func readArrayValues[T any](r io.Reader, length uint32) []T {
+func readArrayValues[T any](d *decoder.Decoder, length uint32, readValue func(*decoder.Decoder) T) []T {
 	values := make([]T, length)
-	for i := range length {
-		var value T
-		ReadFields(r, &value)
-		values[i] = value
+	for i := range values {
+		values[i] = readValue(d)
 	}
 	return values
 }
 
-func readArrayStructProperty(cr *countingreader.CountingReader, length uint32) saveformat.ArrayStructProperty {
+func readArrayStructProperty(d *decoder.Decoder, length uint32) saveformat.ArrayStructProperty {
 	var p saveformat.ArrayStructProperty
-	ReadFields(cr, &p.Name, &p.Type, &p.Size, &p.Padding, &p.ElementType,
-		&p.Padding1, &p.Padding2, &p.Padding3, &p.Padding4, &p.PaddingByte,
-	)
+	p.Name, p.Type, p.Size, p.Padding, p.ElementType = d.InternedString(), d.InternedString(), d.Uint32(), d.Uint32(), d.InternedString()
+	p.Padding1, p.Padding2, p.Padding3, p.Padding4, p.PaddingByte = d.Uint32(), d.Uint32(), d.Uint32(), d.Uint32(), d.Byte()
 
 	read := func() uint32 {
+		p.Value = make([]any, 0, length)
 		for range length {
-			value := readTypedData(cr, p.ElementType)
+			value := readTypedData(d, p.ElementType)
 			p.Value = append(p.Value, value)
 		}
 		return p.Size
 	}
-	countingreader.ReadAndYeet(cr, read)
+	p.Trailing = decoder.ReadAndKeep(d, read)
 	return p
 }
 
-func readTypedData(cr *countingreader.CountingReader, elementType string) any {
+func readTypedData(d *decoder.Decoder, elementType string) any {
 	var value any
 	switch elementType {
 	case "Box":
 		var v Box
-		ReadFields(cr, &v.MinX, &v.MinY, &v.MinZ, &v.MaxX, &v.MaxY, &v.MaxZ, &v.IsValid)
+		v.MinX, v.MinY, v.MinZ = d.Float64(), d.Float64(), d.Float64()
+		v.MaxX, v.MaxY, v.MaxZ = d.Float64(), d.Float64(), d.Float64()
+		v.IsValid = d.Byte()
 		value = v
 	case "FluidBox":
-		var v FluidBox
-		ReadFields(cr, &v.Value)
-		value = v
-	case "Vector":
-		var v Vector
-		ReadFields(cr, &v.X, &v.Y, &v.Z)
-		value = v
+		value = FluidBox{Value: d.Float32()}
+	case "Vector", "Vector_NetQuantize":
+		value = Vector{X: d.Float64(), Y: d.Float64(), Z: d.Float64()}
 	case "DateTime":
-		var v DateTime
-		ReadFields(cr, &v.Timestamp)
-		value = v
+		value = DateTime{Timestamp: d.Int64()}
 	case "InventoryItem":
 		var v saveformat.InventoryItem
-		ReadFields(cr, &v.Reference, &v.ItemHasProperties)
+		v.Reference, v.ItemHasProperties = ReadObjectReference(d), d.Uint32()
 
 		if v.ItemHasProperties != 0 {
-			ReadFields(cr, &v.ItemType, &v.PropertySize)
-			ReadAllProperties(cr, &v.Properties)
+			v.ItemType, v.PropertySize = ReadObjectReference(d), d.Uint32()
+			ReadAllProperties(d, &v.Properties)
 		}
 		value = v
 	case "LinearColor":
-		var v LinearColor
-		ReadFields(cr, &v.R, &v.G, &v.B, &v.A)
-		value = v
+		value = LinearColor{R: d.Float32(), G: d.Float32(), B: d.Float32(), A: d.Float32()}
 	case "Quat":
-		var v Quat
-		ReadFields(cr, &v.X, &v.Y, &v.Z, &v.W)
-		value = v
+		value = Quat{X: d.Float64(), Y: d.Float64(), Z: d.Float64(), W: d.Float64()}
 	case "RailroadTrackPosition":
-		var v RailroadTrackPosition
-		ReadFields(cr, &v.ObjectRef, &v.Offset, &v.Forward)
-		value = v
+		value = RailroadTrackPosition{ObjectRef: ReadObjectReference(d), Offset: d.Float32(), Forward: d.Float32()}
 	case "Guid":
-		//todo hex type? we will need the length
-		var v1, v2 int64
-		ReadFields(cr, &v1, &v2)
-		value = v1
+		var v Guid
+		d.ReadInto(v[:])
+		value = v
 	case "ClientIdentityInfo":
 		var v ClientIdentityInfo
-		ReadFields(cr, &v.UUID, &v.IdentityCount)
+		v.UUID, v.IdentityCount = d.String(), d.Uint32()
 		for range v.IdentityCount {
 			var id Identity
-			ReadFields(cr, &id.Type, &id.DataSize)
-			id.Data = make([]byte, id.DataSize)
-			err := binary.Read(cr, binary.LittleEndian, &id.Data)
-			if err != nil {
-				panic(err)
-			}
+			id.Type, id.DataSize = d.Byte(), d.Uint32()
+			id.Data = d.Bytes(int(id.DataSize))
 			v.Identities = append(v.Identities, id)
 		}
 		value = v
 	default:
 		props := make([]saveformat.Property, 0)
-		ReadAllProperties(cr, &props)
+		ReadAllProperties(d, &props)
 		value = props
 	}
 	return value
 }
 
-func readArrayProperty(cr *countingreader.CountingReader) any {
+func readSoftObjectReference(d *decoder.Decoder) ArraySoftObjectProperty {
+	return ArraySoftObjectProperty{Reference: ReadObjectReference(d), Value: d.Uint32()}
+}
+
+type arrayValuesReader func(d *decoder.Decoder, length uint32) any
+
+func readArrayValuesOf[T any](readValue func(*decoder.Decoder) T) arrayValuesReader {
+	return func(d *decoder.Decoder, length uint32) any {
+		return readArrayValues(d, length, readValue)
+	}
+}
+
+var arrayValuesReaders = map[string]arrayValuesReader{
+	"ByteProperty":       func(d *decoder.Decoder, length uint32) any { return d.Bytes(int(length)) },
+	"EnumProperty":       readArrayValuesOf((*decoder.Decoder).InternedString),
+	"StrProperty":        readArrayValuesOf((*decoder.Decoder).String),
+	"ObjectProperty":     readArrayValuesOf(ReadObjectReference),
+	"InterfaceProperty":  readArrayValuesOf(ReadObjectReference),
+	"IntProperty":        readArrayValuesOf((*decoder.Decoder).Int32),
+	"Int64Property":      readArrayValuesOf((*decoder.Decoder).Int64),
+	"FloatProperty":      readArrayValuesOf((*decoder.Decoder).Float32),
+	"SoftObjectProperty": readArrayValuesOf(readSoftObjectReference),
+}
+
+func readArrayProperty(d *decoder.Decoder, prop *saveformat.Property) {
 	var p ArrayProperty
-	ReadFields(cr, &p.Size, &p.Index, &p.Type, &p.Padding, &p.Length)
+	p.Size, p.Index, p.Type, p.Padding = d.Uint32(), d.Uint32(), d.InternedString(), d.Byte()
+	prop.Index, prop.InnerType = p.Index, p.Type
 
-	var values any
-	switch p.Type {
-	case "ByteProperty":
-		values = readArrayValues[byte](cr, p.Length)
-	case "EnumProperty", "StrProperty":
-		values = readArrayValues[string](cr, p.Length)
-	case "ObjectProperty", "InterfaceProperty":
-		values = readArrayValues[saveformat.ObjectReference](cr, p.Length)
-	case "IntProperty":
-		values = readArrayValues[int32](cr, p.Length)
-	case "Int64Property":
-		values = readArrayValues[int64](cr, p.Length)
-	case "FloatProperty":
-		values = readArrayValues[float32](cr, p.Length)
-	case "SoftObjectProperty":
-		values = readArrayValues[ArraySoftObjectProperty](cr, p.Length)
-	case "StructProperty":
-		values = readArrayStructProperty(cr, p.Length)
+	if p.Type == "StructProperty" {
+		p.Length = d.Uint32()
+		prop.Value = readArrayStructProperty(d, p.Length)
+		return
+	}
 
-	default:
-		eatPropertyData(cr, p.Size-4)
+	readValues, ok := arrayValuesReaders[p.Type]
+	if !ok {
+		prop.Raw = d.Bytes(int(p.Size))
+		return
 	}
 
-	return values
+	p.Length = d.Uint32()
+	prop.Value = readValues(d, p.Length)
 }
@@ -7,11 +7,19 @@ import (
 	"unicode/utf16"
 	"unicode/utf8"
 
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
+// ReadFields reads each field in order. When r is a *decoder.Decoder the fields are
+// read from its buffer directly, other readers fall back to binary.Read.
 func ReadFields(r io.Reader, fields ...any) {
+	d, isDecoder := r.(*decoder.Decoder)
 	for _, field := range fields {
+		if isDecoder && decodeField(d, field) {
+			continue
+		}
+
 		switch field := field.(type) {
 		case *string:
 			s, err := readString(r)
@@ -36,6 +44,39 @@ func ReadFields(r io.Reader, fields ...any) {
 	}
 }
 
+// decodeField reads basic fields with the decoder, returns false for other field types.
+func decodeField(d *decoder.Decoder, field any) bool {
+	switch field := field.(type) {
+	case *string:
+		*field = d.String()
+	case *byte:
+		*field = d.Byte()
+	case *int8:
+		*field = d.Int8()
+	case *uint32:
+		*field = d.Uint32()
+	case *int32:
+		*field = d.Int32()
+	case *uint64:
+		*field = d.Uint64()
+	case *int64:
+		*field = d.Int64()
+	case *float32:
+		*field = d.Float32()
+	case *float64:
+		*field = d.Float64()
+	case *saveformat.ObjectReference:
+		*field = ReadObjectReference(d)
+	default:
+		return false
+	}
+	return true
+}
+
+func ReadObjectReference(d *decoder.Decoder) saveformat.ObjectReference {
+	return saveformat.ObjectReference{LevelName: d.InternedString(), PathName: d.String()}
+}
+
 func ConditionalFields(useValue bool, fields ...any) any {
 	if useValue {
 		return fields
@@ -0,0 +1,51 @@
+package readfields
+
+import (
+	"fmt"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
+)
+
+const (
+	textHistoryNone             int8 = -1
+	textHistoryBase             int8 = 0
+	textHistoryNamedFormat      int8 = 1
+	textHistoryOrderedFormat    int8 = 2
+	textHistoryArgumentFormat   int8 = 3
+	textHistoryStringTableEntry int8 = 11
+)
+
+// ReadText reads the display string of a text property value. Formatted texts return their
+// format pattern and string table entries their key, since the tables are not in the save.
+// Unknown history types and truncated values are returned as errors.
+func ReadText(d *decoder.Decoder) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("reading text: %v", r)
+		}
+	}()
+	return readText(d)
+}
+
+func readText(d *decoder.Decoder) (string, error) {
+	var p TextProperty
+	p.Flags, p.HistoryType = d.Uint32(), d.Int8()
+
+	switch p.HistoryType {
+	case textHistoryNone:
+		if p.IsCultureInvariant = d.Uint32(); p.IsCultureInvariant != 0 {
+			p.Value = d.String()
+		}
+	case textHistoryBase:
+		_, _ = d.String(), d.String() // namespace and key
+		p.Value = d.String()
+	case textHistoryNamedFormat, textHistoryOrderedFormat, textHistoryArgumentFormat:
+		return readText(d)
+	case textHistoryStringTableEntry:
+		_ = d.String() // table id
+		p.Value = d.String()
+	default:
+		return "", fmt.Errorf("not implemented text history type: %d", p.HistoryType)
+	}
+	return p.Value, nil
+}
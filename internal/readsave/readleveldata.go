@@ -2,132 +2,255 @@ package readsave
 
 import (
 	"fmt"
-	"io"
 
-	"github.com/Maurits825/satisfactory-savefile-parser/internal/countingreader"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
 	. "github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
-func readLevelData(cr *countingreader.CountingReader, version uint32, isPersistentLevel bool) *saveformat.LevelData {
+func readLevelData(d *decoder.Decoder, version uint32, isPersistentLevel bool, v Visitor, opts Options) *saveformat.LevelData {
 	var levelData saveformat.LevelData
 
 	if !isPersistentLevel {
-		ReadFields(cr, &levelData.Name)
+		levelData.Name = d.InternedString()
 	}
 
-	ReadFields(cr, &levelData.Size, &levelData.HeaderCount)
+	ReadFields(d, &levelData.Size, &levelData.HeaderCount)
 
-	startPos := cr.Position()
-	headerTypes := readLevelHeader(cr, &levelData, version)
-	endPos := cr.Position()
+	startPos := d.Position()
+	headerEnds := readLevelHeader(d, &levelData, version, opts.ClassFilter != nil)
+	endPos := d.Position()
 
 	bytesRead := uint64(endPos - startPos)
 	diff := levelData.Size - bytesRead
 	if diff > 4 {
-		ReadFields(cr, &levelData.CollectableCount)
+		levelData.HasCollectables = true
+		ReadFields(d, &levelData.CollectableCount)
 		if levelData.CollectableCount > 0 && isPersistentLevel {
-			var name string
-			ReadFields(cr, &name, &levelData.CollectableCount)
-		}
-		for range levelData.CollectableCount {
-			var collectable saveformat.ObjectReference
-			ReadFields(cr, &collectable.LevelName, &collectable.PathName)
+			levelData.CollectablesLevelCount = levelData.CollectableCount
+			ReadFields(d, &levelData.CollectablesLevelName, &levelData.CollectableCount)
 		}
+		levelData.Collectables = readObjectReferences(d, levelData.CollectableCount)
 	}
 
-	ReadFields(cr, &levelData.ObjectSize, &levelData.ObjectCount)
+	ReadFields(d, &levelData.ObjectSize, &levelData.ObjectCount)
 
-	for i := range levelData.ObjectCount {
-		read := func() uint32 {
-			objectSize := readLevelObject(cr, &levelData, headerTypes[i])
-			objectSize += 12 // 12 bytes for SaveVersion, Flag, Size
-			return objectSize
+	objectCount, headerTypes, actorHeaders, componentHeaders := levelData.ObjectCount, levelData.HeaderTypes, levelData.ActorHeaders, levelData.ComponentHeaders
+	var keep []bool
+	if opts.ClassFilter != nil {
+		keep = filterHeaders(&levelData, opts.ClassFilter, startPos, headerEnds)
+	}
+
+	var lazy func(data []byte) *saveformat.LazyProperties
+	if opts.LazyProperties {
+		lazy = newLazyProperties
+	}
+	v.OnLevelStart(&levelData)
+
+	actorIndex, componentIndex := 0, 0
+	for i := range objectCount {
+		if keep != nil && !keep[i] {
+			levelData.ObjectSize -= skipLevelObject(d)
+		} else if headerTypes[i] == 0 {
+			readComponentObject(d, &componentHeaders[componentIndex], v, lazy)
+		} else {
+			readActorObject(d, &actorHeaders[actorIndex], v, lazy)
+		}
+
+		if headerTypes[i] == 0 {
+			componentIndex++
+		} else {
+			actorIndex++
 		}
-		countingreader.ReadAndYeet(cr, read)
 	}
 
 	if !isPersistentLevel && version >= 51 {
-		var v uint32
-		ReadFields(cr, &v)
+		ReadFields(d, &levelData.SaveVersion)
 	}
 
-	ReadFields(cr, &levelData.SecondCollectableCount)
-	for range levelData.SecondCollectableCount {
-		var collectable saveformat.ObjectReference
-		ReadFields(cr, &collectable.LevelName, &collectable.PathName)
-	}
+	ReadFields(d, &levelData.SecondCollectableCount)
+	levelData.SecondCollectables = readObjectReferences(d, levelData.SecondCollectableCount)
+	v.OnLevelEnd(&levelData)
 
 	return &levelData
 }
 
-func readLevelHeader(r io.Reader, levelData *saveformat.LevelData, version uint32) []uint32 {
-	headerTypes := make([]uint32, 0, levelData.HeaderCount)
+func readObjectReferences(d *decoder.Decoder, count uint32) []saveformat.ObjectReference {
+	references := make([]saveformat.ObjectReference, count)
+	for i := range references {
+		references[i] = ReadObjectReference(d)
+	}
+	return references
+}
+
+// readLevelHeader reads the object headers of a level. With recordEnds it returns the end
+// position of each header, to update the level size when headers are filtered out.
+func readLevelHeader(d *decoder.Decoder, levelData *saveformat.LevelData, version uint32, recordEnds bool) []int64 {
+	levelData.HeaderTypes = make([]uint32, 0, levelData.HeaderCount)
+	var ends []int64
+	if recordEnds {
+		ends = make([]int64, 0, levelData.HeaderCount)
+	}
 
 	for range levelData.HeaderCount {
-		var headerType uint32
-		ReadFields(r, &headerType)
-		headerTypes = append(headerTypes, headerType)
+		headerType := d.Uint32()
+		levelData.HeaderTypes = append(levelData.HeaderTypes, headerType)
 		if headerType == 0 {
 			var componentHeader saveformat.ComponentHeader
-			ReadFields(r,
-				&componentHeader.TypePath, &componentHeader.Root,
-				&componentHeader.Name, ConditionalFields(version >= 51, &componentHeader.Flags),
-				&componentHeader.ParentActorName,
-			)
+			componentHeader.TypePath, componentHeader.Root, componentHeader.Name = d.InternedString(), d.InternedString(), d.String()
+			if version >= 51 {
+				componentHeader.Flags = d.Uint32()
+			}
+			componentHeader.ParentActorName = d.String()
 			levelData.ComponentHeaders = append(levelData.ComponentHeaders, componentHeader)
 
 		} else if headerType == 1 {
 			var actorHeader saveformat.ActorHeader
-			ReadFields(r,
-				&actorHeader.TypePath, &actorHeader.Root, &actorHeader.Name,
-				ConditionalFields(version >= 51, &actorHeader.Flags), &actorHeader.NeedTransform,
-				&actorHeader.RotationX, &actorHeader.RotationY, &actorHeader.RotationZ, &actorHeader.RotationW,
-				&actorHeader.PositionX, &actorHeader.PositionY, &actorHeader.PositionZ,
-				&actorHeader.ScaleX, &actorHeader.ScaleY, &actorHeader.ScaleZ,
-				&actorHeader.WasPlaced,
-			)
+			actorHeader.TypePath, actorHeader.Root, actorHeader.Name = d.InternedString(), d.InternedString(), d.String()
+			if version >= 51 {
+				actorHeader.Flags = d.Uint32()
+			}
+			actorHeader.NeedTransform = d.Uint32()
+			actorHeader.RotationX, actorHeader.RotationY, actorHeader.RotationZ, actorHeader.RotationW = d.Float32(), d.Float32(), d.Float32(), d.Float32()
+			actorHeader.PositionX, actorHeader.PositionY, actorHeader.PositionZ = d.Float32(), d.Float32(), d.Float32()
+			actorHeader.ScaleX, actorHeader.ScaleY, actorHeader.ScaleZ = d.Float32(), d.Float32(), d.Float32()
+			actorHeader.WasPlaced = d.Uint32()
 			levelData.ActorHeaders = append(levelData.ActorHeaders, actorHeader)
 
 		} else {
 			panic("Unknown header type: " + fmt.Sprint(headerType))
 		}
+		if recordEnds {
+			ends = append(ends, d.Position())
+		}
+	}
+	return ends
+}
+
+// filterHeaders removes the headers of the objects rejected by filter from the level and updates
+// its counts and size. Components are kept when their owning actor is, also when nested in other
+// components. headerEnds are the end positions of the headers, which start at headerStart.
+// It returns which objects to keep, in file order.
+func filterHeaders(levelData *saveformat.LevelData, filter ClassFilter, headerStart int64, headerEnds []int64) []bool {
+	kept := make(map[string]bool)
+	var actorHeaders []saveformat.ActorHeader
+	for _, header := range levelData.ActorHeaders {
+		if filter(header.TypePath) {
+			kept[header.Name] = true
+			actorHeaders = append(actorHeaders, header)
+		}
+	}
+
+	parents := make(map[string]string, len(levelData.ComponentHeaders))
+	for _, header := range levelData.ComponentHeaders {
+		parents[header.Name] = header.ParentActorName
+	}
+	isKept := func(name string) bool {
+		visited := make(map[string]bool)
+		for !visited[name] {
+			if kept[name] {
+				return true
+			}
+			visited[name] = true
+			parent, ok := parents[name]
+			if !ok {
+				return false
+			}
+			name = parent
+		}
+		return false
 	}
 
-	return headerTypes
+	keep := make([]bool, len(levelData.HeaderTypes))
+	var headerTypes []uint32
+	var componentHeaders []saveformat.ComponentHeader
+	actorIndex, componentIndex := 0, 0
+	for i, headerType := range levelData.HeaderTypes {
+		if headerType == 0 {
+			header := levelData.ComponentHeaders[componentIndex]
+			componentIndex++
+			keep[i] = isKept(header.ParentActorName)
+			if keep[i] {
+				componentHeaders = append(componentHeaders, header)
+			}
+		} else {
+			keep[i] = kept[levelData.ActorHeaders[actorIndex].Name]
+			actorIndex++
+		}
+
+		if keep[i] {
+			headerTypes = append(headerTypes, headerType)
+		} else {
+			start := headerStart
+			if i > 0 {
+				start = headerEnds[i-1]
+			}
+			levelData.Size -= uint64(headerEnds[i] - start)
+		}
+	}
+
+	levelData.HeaderTypes, levelData.ActorHeaders, levelData.ComponentHeaders = headerTypes, actorHeaders, componentHeaders
+	levelData.HeaderCount, levelData.ObjectCount = uint32(len(headerTypes)), uint32(len(headerTypes))
+	return keep
+}
+
+// skipLevelObject skips an object and returns its size in the level.
+func skipLevelObject(d *decoder.Decoder) uint64 {
+	d.Uint32() // SaveVersion
+	d.Uint32() // Flag
+	size := d.Uint32()
+	d.Skip(int64(size))
+	return 12 + uint64(size)
 }
 
-func readLevelObject(cr *countingreader.CountingReader, levelData *saveformat.LevelData, headerType uint32) uint32 {
-	var objectSize uint32
-	if headerType == 0 {
-		var component saveformat.ComponentObject
-		ReadFields(cr, &component.SaveVersion, &component.Flag, &component.Size)
-		objectSize = component.Size
-		ReadAllProperties(cr, &component.Properties)
+// newLazyProperties keeps an undecoded property block of a level.
+func newLazyProperties(data []byte) *saveformat.LazyProperties {
+	return &saveformat.LazyProperties{Data: data, Decode: DecodeRawProperties}
+}
 
-		if !component.IsValid() {
-			panic("Invalid component object")
+func readComponentObject(d *decoder.Decoder, header *saveformat.ComponentHeader, v Visitor, lazy func([]byte) *saveformat.LazyProperties) {
+	var component saveformat.ComponentObject
+	component.SaveVersion, component.Flag, component.Size = d.Uint32(), d.Uint32(), d.Uint32()
+	if lazy != nil {
+		component.Lazy = lazy(d.Bytes(int(component.Size)))
+	} else {
+		read := func() uint32 {
+			ReadAllProperties(d, &component.Properties)
+			return component.Size
 		}
+		component.Trailing = decoder.ReadAndKeep(d, read)
+	}
+
+	if !component.IsValid() {
+		panic("Invalid component object")
+	}
 
-		levelData.ComponentObjects = append(levelData.ComponentObjects, component)
-	} else if headerType == 1 {
-		var actor saveformat.ActorObject
-		ReadFields(cr, &actor.SaveVersion, &actor.Flag, &actor.Size,
-			&actor.ParentReference, &actor.ComponentCount)
-		objectSize = actor.Size
+	v.OnComponent(header, &component)
+}
+
+func readActorObject(d *decoder.Decoder, header *saveformat.ActorHeader, v Visitor, lazy func([]byte) *saveformat.LazyProperties) {
+	var actor saveformat.ActorObject
+	actor.SaveVersion, actor.Flag, actor.Size = d.Uint32(), d.Uint32(), d.Uint32()
+	read := func() uint32 {
+		actor.ParentReference, actor.ComponentCount = ReadObjectReference(d), d.Uint32()
 		for range actor.ComponentCount {
-			var component saveformat.ObjectReference
-			ReadFields(cr, &component.LevelName, &component.PathName)
-			actor.Components = append(actor.Components, component)
+			actor.Components = append(actor.Components, ReadObjectReference(d))
 		}
-		ReadAllProperties(cr, &actor.Properties)
-
-		if !actor.IsValid() {
-			panic("Invalid actor object")
+		if lazy == nil {
+			ReadAllProperties(d, &actor.Properties)
 		}
+		return actor.Size
+	}
+	rest := decoder.ReadAndKeep(d, read)
+	if lazy != nil {
+		actor.Lazy = lazy(rest)
+	} else {
+		actor.Trailing = rest
+	}
 
-		levelData.ActorObjects = append(levelData.ActorObjects, actor)
+	if !actor.IsValid() {
+		panic("Invalid actor object")
 	}
 
-	return objectSize
+	v.OnActor(header, &actor)
 }
@@ -1,41 +1,63 @@
 package readsave
 
 import (
+	"bufio"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"time"
 
-	"github.com/Maurits825/satisfactory-savefile-parser/internal/countingreader"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
 	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
-func ReadSave(file *os.File) *saveformat.SaveFileBody {
+// ClassFilter reports whether actors of the given type path should be decoded.
+// It must be safe for concurrent use when levels are decoded in parallel.
+type ClassFilter func(typePath string) bool
+
+type Options struct {
+	// ParallelLevels decompresses the whole body into memory and decodes the levels concurrently,
+	// when the body is larger than MinParallelBytes and has at least MinParallelLevels levels.
+	ParallelLevels bool
+	// ClassFilter keeps only the accepted actors and their components, all other
+	// objects are skipped without decoding their properties.
+	ClassFilter ClassFilter
+	// LazyProperties keeps the property block of each object undecoded until
+	// DecodeProperties is called on it.
+	LazyProperties bool
+}
+
+func ReadSave(file *os.File, opts Options) *saveformat.SaveFileBody {
 	header := readHeader(file)
 	fmt.Printf("Save name: %s, Version: %d-%d-%d\n", header.SessionName, header.SaveVersion, header.SaveHeaderVersion, header.BuildVersion)
 	fmt.Println("")
 
-	readers := make([]io.Reader, 0)
-	totalSize := uint64(0)
-	fmt.Println("Decompressing save file body")
-	for {
-		zr, size := readCompressedSaveFileBody(file)
-		if size == 0 {
-			break
+	zr, totalSize := decompressBody(file)
+	defer zr.Close()
+
+	var d *decoder.Decoder
+	readLevels := sequentialLevelsReader(opts)
+	if opts.ParallelLevels {
+		data := make([]byte, totalSize)
+		if _, err := io.ReadFull(zr, data); err != nil {
+			panic("decompressing body: " + err.Error())
 		}
-		totalSize += size
-		readers = append(readers, zr)
+		d = decoder.NewBytesDecoder(data)
+		readLevels = parallelLevelsReader(data, opts)
+	} else {
+		d = decoder.NewDecoder(zr)
 	}
 
-	multiZr := io.MultiReader(readers...)
-	cr := countingreader.NewCountingReader(multiZr)
+	d.SetInterner(decoder.NewInterner())
 
 	startTime := time.Now()
-	statusUpdate := newStatusTicker(1*time.Second, func() { statusPrint(cr, totalSize, startTime) })
+	statusUpdate := newStatusTicker(1*time.Second, func() { statusPrint(d, totalSize, startTime) })
 	statusUpdate.start()
 
-	body := readSaveFileBody(cr, header.SaveVersion)
+	body := readSaveFileBody(d, header.SaveVersion, readLevels)
 
 	statusUpdate.stop()
 	tDiff := float64(time.Since(startTime).Seconds())
@@ -44,6 +66,56 @@ func ReadSave(file *os.File) *saveformat.SaveFileBody {
 	return body
 }
 
+// ReadBody reads the save file header and fully decompresses the body into memory.
+func ReadBody(file *os.File) (*saveformat.SaveFileHeader, []byte) {
+	header := readHeader(file)
+
+	zr, totalSize := decompressBody(file)
+	defer zr.Close()
+	data := make([]byte, totalSize)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		panic("decompressing body: " + err.Error())
+	}
+	return header, data
+}
+
+// ParseBody parses an already decompressed save file body.
+func ParseBody(data []byte, version uint32, opts Options) *saveformat.SaveFileBody {
+	d := decoder.NewBytesDecoder(data)
+	d.SetInterner(decoder.NewInterner())
+	readLevels := sequentialLevelsReader(opts)
+	if opts.ParallelLevels {
+		readLevels = parallelLevelsReader(data, opts)
+	}
+	return readSaveFileBody(d, version, readLevels)
+}
+
+type bodyReader struct {
+	*bufio.Reader
+	chunks *chunkReader
+}
+
+func (r *bodyReader) Close() error {
+	return r.chunks.Close()
+}
+
+func decompressBody(file io.Reader) (io.ReadCloser, uint64) {
+	fmt.Println("Decompressing save file body")
+	workers := runtime.NumCPU()
+	chunks := newChunkReader(file, workers, 2*workers)
+	br := bufio.NewReaderSize(chunks, 64*1024)
+
+	// the body starts with its own size, excluding the size field
+	sizeBytes, err := br.Peek(8)
+	if err != nil {
+		chunks.Close()
+		panic("reading body size: " + err.Error())
+	}
+	totalSize := binary.LittleEndian.Uint64(sizeBytes) + 8
+
+	return &bodyReader{Reader: br, chunks: chunks}, totalSize
+}
+
 func readHeader(file io.Reader) *saveformat.SaveFileHeader {
 	header := &saveformat.SaveFileHeader{}
 
@@ -59,8 +131,8 @@ func readHeader(file io.Reader) *saveformat.SaveFileHeader {
 	return header
 }
 
-func statusPrint(cr *countingreader.CountingReader, total uint64, startTime time.Time) {
-	pos := cr.Position()
+func statusPrint(d *decoder.Decoder, total uint64, startTime time.Time) {
+	pos := d.Position()
 	percent := float64(pos) / float64(total) * 100.0
 
 	tDiff := float64(time.Since(startTime).Seconds())
@@ -0,0 +1,64 @@
+package readsave
+
+import (
+	"io"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// Visitor receives the save while it is being read. Each object is passed on as
+// soon as it is decoded and is not kept by the reader afterwards.
+type Visitor interface {
+	OnHeader(header *saveformat.SaveFileHeader)
+	// OnLevelStart is called once the headers and collectables of a level are read, before its objects.
+	OnLevelStart(level *saveformat.LevelData)
+	OnActor(header *saveformat.ActorHeader, actor *saveformat.ActorObject)
+	OnComponent(header *saveformat.ComponentHeader, component *saveformat.ComponentObject)
+	OnLevelEnd(level *saveformat.LevelData)
+}
+
+// levelCollector keeps every object on its level, building the full LevelData.
+type levelCollector struct {
+	level *saveformat.LevelData
+}
+
+func (c *levelCollector) OnHeader(header *saveformat.SaveFileHeader) {}
+
+func (c *levelCollector) OnLevelStart(level *saveformat.LevelData) {
+	c.level = level
+}
+
+func (c *levelCollector) OnActor(header *saveformat.ActorHeader, actor *saveformat.ActorObject) {
+	c.level.ActorObjects = append(c.level.ActorObjects, *actor)
+}
+
+func (c *levelCollector) OnComponent(header *saveformat.ComponentHeader, component *saveformat.ComponentObject) {
+	c.level.ComponentObjects = append(c.level.ComponentObjects, *component)
+}
+
+func (c *levelCollector) OnLevelEnd(level *saveformat.LevelData) {}
+
+// Walk reads the save and passes every level and object to v without keeping them,
+// so memory use does not grow with the size of the save. Strings are not interned,
+// since the objects holding them are dropped after their callback.
+func Walk(r io.Reader, v Visitor, opts Options) {
+	header := readHeader(r)
+	v.OnHeader(header)
+
+	zr, _ := decompressBody(r)
+	defer zr.Close()
+
+	d := decoder.NewDecoder(zr)
+	readSaveFileBody(d, header.SaveVersion, walkLevels(v, opts))
+}
+
+func walkLevels(v Visitor, opts Options) levelsReader {
+	return func(d *decoder.Decoder, subLevelCount uint32, version uint32) []saveformat.LevelData {
+		for range subLevelCount {
+			readLevelData(d, version, false, v, opts)
+		}
+		readLevelData(d, version, true, v, opts)
+		return nil
+	}
+}
@@ -0,0 +1,69 @@
+// Package testsave loads the test save and builds small synthetic levels for the package tests.
+package testsave
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// Path is the creative test save in the parser test data.
+var Path = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "pkg", "parser", "testdata", "test_creative_v1.1_exp.sav")
+}()
+
+// LoadBody parses the test save, failing the test when it can not be parsed.
+func LoadBody(t testing.TB, opts ...parser.Option) *saveformat.SaveFileBody {
+	t.Helper()
+	body := parser.ParseSaveFile(Path, opts...)
+	if body == nil {
+		t.Fatal("Parsing test save failed")
+	}
+	return body
+}
+
+// Load parses and indexes the test save, failing the test when it can not be parsed.
+func Load(t testing.TB, opts ...parser.Option) *saveformat.SaveIndex {
+	t.Helper()
+	return saveformat.NewSaveIndex(LoadBody(t, opts...))
+}
+
+func Ref(pathName string) saveformat.ObjectReference {
+	return saveformat.ObjectReference{PathName: pathName}
+}
+
+// Level builds a level of actors and components with decoded properties.
+type Level struct {
+	saveformat.LevelData
+}
+
+// AddActor adds an actor with the type path "/Game/" + class. The returned header is only
+// valid until the next actor is added.
+func (level *Level) AddActor(name string, class string, props ...saveformat.Property) *saveformat.ActorHeader {
+	level.HeaderTypes = append(level.HeaderTypes, 1)
+	level.ActorHeaders = append(level.ActorHeaders, saveformat.ActorHeader{TypePath: "/Game/" + class, Name: name})
+	level.ActorObjects = append(level.ActorObjects, saveformat.ActorObject{Properties: props})
+	return &level.ActorHeaders[len(level.ActorHeaders)-1]
+}
+
+// AddComponent adds a component of the given type path to the component list of its parent actor,
+// which must be added before.
+func (level *Level) AddComponent(name string, typePath string, parent string, props ...saveformat.Property) {
+	level.HeaderTypes = append(level.HeaderTypes, 0)
+	level.ComponentHeaders = append(level.ComponentHeaders, saveformat.ComponentHeader{TypePath: typePath, Name: name, ParentActorName: parent})
+	level.ComponentObjects = append(level.ComponentObjects, saveformat.ComponentObject{Properties: props})
+	for i := range level.ActorHeaders {
+		if level.ActorHeaders[i].Name == parent {
+			level.ActorObjects[i].Components = append(level.ActorObjects[i].Components, Ref(name))
+		}
+	}
+}
+
+// Index links and indexes the level as the only level of a save.
+func (level *Level) Index() *saveformat.SaveIndex {
+	return saveformat.NewSaveIndex(&saveformat.SaveFileBody{Levels: []saveformat.LevelData{level.LevelData}})
+}
@@ -0,0 +1,82 @@
+package trackingwriter
+
+import (
+	"encoding/binary"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// TrackingWriter buffers written data in memory and keeps track of the path
+// (level, object, property, ...) of the value currently being written.
+// When a target offset is set, the innermost path containing it is recorded.
+type TrackingWriter struct {
+	buf    []byte
+	path   []string
+	target int64
+	found  []string
+
+	encodings saveformat.StringEncodings
+}
+
+func NewTrackingWriter(target int64) *TrackingWriter {
+	return &TrackingWriter{target: target}
+}
+
+func (w *TrackingWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *TrackingWriter) Position() int64 {
+	return int64(len(w.buf))
+}
+
+func (w *TrackingWriter) Bytes() []byte {
+	return w.buf
+}
+
+// SetStringEncodings makes strings be written in the encoding they were read with.
+func (w *TrackingWriter) SetStringEncodings(e saveformat.StringEncodings) {
+	w.encodings = e
+}
+
+// StringEncoding returns the encoding s was read with, if it is not the default one.
+func (w *TrackingWriter) StringEncoding(s string) (saveformat.StringEncoding, bool) {
+	e, ok := w.encodings[s]
+	return e, ok
+}
+
+// Reserve writes n zero bytes to be patched later and returns their position.
+func (w *TrackingWriter) Reserve(n int) int64 {
+	pos := w.Position()
+	w.buf = append(w.buf, make([]byte, n)...)
+	return pos
+}
+
+func (w *TrackingWriter) PatchUint32(pos int64, v uint32) {
+	binary.LittleEndian.PutUint32(w.buf[pos:], v)
+}
+
+func (w *TrackingWriter) PatchUint64(pos int64, v uint64) {
+	binary.LittleEndian.PutUint64(w.buf[pos:], v)
+}
+
+// Enter pushes a path segment and returns the current position, to be passed to Leave:
+//
+//	defer w.Leave(w.Enter(name))
+func (w *TrackingWriter) Enter(segment string) int64 {
+	w.path = append(w.path, segment)
+	return w.Position()
+}
+
+func (w *TrackingWriter) Leave(start int64) {
+	if w.found == nil && start <= w.target && w.target < w.Position() {
+		w.found = append([]string{}, w.path...)
+	}
+	w.path = w.path[:len(w.path)-1]
+}
+
+// FoundPath returns the innermost path that contains the target offset, if any.
+func (w *TrackingWriter) FoundPath() []string {
+	return w.found
+}
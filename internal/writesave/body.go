@@ -0,0 +1,49 @@
+package writesave
+
+import (
+	"fmt"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/trackingwriter"
+	. "github.com/Maurits825/satisfactory-savefile-parser/internal/writesave/writefields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func writeSaveFileBody(w *trackingwriter.TrackingWriter, body *saveformat.SaveFileBody, version uint32) {
+	w.SetStringEncodings(body.StringEncodings)
+	sizePos := w.Reserve(8)
+	WriteFields(w,
+		body.Value6, body.NoneString1, body.Value0,
+		body.Unknown1, body.Value1, body.NoneString2, body.Unknown2,
+	)
+
+	for i, grid := range body.LevelGroupingGrids {
+		start := w.Enter(fmt.Sprintf("LevelGroupingGrids[%d]", i))
+		writeLevelGroupingGrid(w, grid)
+		w.Leave(start)
+	}
+
+	subLevels := body.Levels[:len(body.Levels)-1]
+	WriteFields(w, uint32(len(subLevels)))
+	for _, level := range subLevels {
+		writeLevelData(w, level, version, false)
+	}
+
+	writeLevelData(w, body.Levels[len(body.Levels)-1], version, true)
+
+	start := w.Enter("References")
+	WriteFields(w, uint32(len(body.References)))
+	for _, reference := range body.References {
+		WriteFields(w, reference)
+	}
+	w.Leave(start)
+
+	w.PatchUint64(sizePos, uint64(w.Position()-8))
+}
+
+func writeLevelGroupingGrid(w *trackingwriter.TrackingWriter, grid saveformat.LevelGroupingGrid) {
+	WriteFields(w, grid.GridName, grid.Unknown1, grid.Unknown2, uint32(len(grid.LevelInfos)))
+
+	for _, levelInfo := range grid.LevelInfos {
+		WriteFields(w, levelInfo.StringValue, levelInfo.IntValue)
+	}
+}
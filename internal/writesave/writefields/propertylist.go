@@ -0,0 +1,150 @@
+package writefields
+
+import (
+	"fmt"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/trackingwriter"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func WriteAllProperties(w *trackingwriter.TrackingWriter, props []saveformat.Property) {
+	for _, p := range props {
+		writeProperty(w, p)
+	}
+}
+
+func writeProperty(w *trackingwriter.TrackingWriter, p saveformat.Property) {
+	defer w.Leave(w.Enter(p.Name))
+
+	WriteFields(w, p.Name)
+	if p.Name == "None" {
+		return
+	}
+
+	WriteFields(w, p.Type)
+	writePropertyData(w, p)
+}
+
+// writeSizedProperty writes Size, Index, the type specific fields and the padding byte,
+// followed by the value. Size is patched with the length of the value afterwards.
+func writeSizedProperty(w *trackingwriter.TrackingWriter, p saveformat.Property, fields []any, writeValue func()) {
+	sizePos := w.Reserve(4)
+	WriteFields(w, p.Index, fields, byte(0))
+
+	start := w.Position()
+	if p.Raw != nil {
+		WriteFields(w, p.Raw)
+	} else {
+		writeValue()
+	}
+	w.PatchUint32(sizePos, uint32(w.Position()-start))
+}
+
+func writeValue(w *trackingwriter.TrackingWriter, value any) func() {
+	return func() { WriteFields(w, value) }
+}
+
+func writePropertyData(w *trackingwriter.TrackingWriter, p saveformat.Property) {
+	switch p.Type {
+	case "IntProperty", "FloatProperty", "DoubleProperty", "Int8Property", "Int64Property",
+		"UInt32Property", "StrProperty", "NameProperty", "ObjectProperty", "SoftObjectProperty":
+		writeSizedProperty(w, p, nil, writeValue(w, p.Value))
+	case "BoolProperty":
+		WriteFields(w, uint32(0), p.Index, p.Value, byte(0))
+	case "ByteProperty", "EnumProperty", "SetProperty":
+		writeSizedProperty(w, p, []any{p.InnerType}, writeValue(w, p.Value))
+	case "StructProperty":
+		writeSizedProperty(w, p, []any{p.InnerType, p.GUID}, func() {
+			writeTypedData(w, p.InnerType, p.Value)
+		})
+	case "ArrayProperty":
+		writeSizedProperty(w, p, []any{p.InnerType}, func() {
+			writeArrayProperty(w, p)
+		})
+	case "MapProperty":
+		writeSizedProperty(w, p, []any{p.InnerType, p.ValueType}, nil)
+	case "TextProperty":
+		writeSizedProperty(w, p, nil, nil)
+	default:
+		panic("not implemented property type: " + p.Type)
+	}
+}
+
+func writeArrayValues[T any](w *trackingwriter.TrackingWriter, values []T) {
+	WriteFields(w, uint32(len(values)))
+	for i, value := range values {
+		start := w.Enter(fmt.Sprintf("[%d]", i))
+		WriteFields(w, value)
+		w.Leave(start)
+	}
+}
+
+func writeArrayStructProperty(w *trackingwriter.TrackingWriter, p saveformat.ArrayStructProperty) {
+	WriteFields(w, uint32(len(p.Value)), p.Name, p.Type)
+	sizePos := w.Reserve(4)
+	WriteFields(w, p.Padding, p.ElementType,
+		p.Padding1, p.Padding2, p.Padding3, p.Padding4, p.PaddingByte,
+	)
+
+	start := w.Position()
+	for i, value := range p.Value {
+		elementStart := w.Enter(fmt.Sprintf("[%d]", i))
+		writeTypedData(w, p.ElementType, value)
+		w.Leave(elementStart)
+	}
+	WriteFields(w, p.Trailing)
+	w.PatchUint32(sizePos, uint32(w.Position()-start))
+}
+
+func writeArrayProperty(w *trackingwriter.TrackingWriter, p saveformat.Property) {
+	switch values := p.Value.(type) {
+	case []byte:
+		writeArrayValues(w, values)
+	case []string:
+		writeArrayValues(w, values)
+	case []saveformat.ObjectReference:
+		writeArrayValues(w, values)
+	case []int32:
+		writeArrayValues(w, values)
+	case []int64:
+		writeArrayValues(w, values)
+	case []float32:
+		writeArrayValues(w, values)
+	case []readfields.ArraySoftObjectProperty:
+		writeArrayValues(w, values)
+	case saveformat.ArrayStructProperty:
+		writeArrayStructProperty(w, values)
+	default:
+		panic(fmt.Sprintf("not implemented array value: %T", p.Value))
+	}
+}
+
+func writeTypedData(w *trackingwriter.TrackingWriter, elementType string, value any) {
+	switch elementType {
+	case "Box", "FluidBox", "Vector", "Vector_NetQuantize", "DateTime", "LinearColor", "Quat", "Guid":
+		WriteFields(w, value)
+	case "InventoryItem":
+		v := value.(saveformat.InventoryItem)
+		WriteFields(w, v.Reference, v.ItemHasProperties)
+
+		if v.ItemHasProperties != 0 {
+			WriteFields(w, v.ItemType)
+			sizePos := w.Reserve(4)
+			start := w.Position()
+			WriteAllProperties(w, v.Properties)
+			w.PatchUint32(sizePos, uint32(w.Position()-start))
+		}
+	case "RailroadTrackPosition":
+		v := value.(readfields.RailroadTrackPosition)
+		WriteFields(w, v.ObjectRef, v.Offset, v.Forward)
+	case "ClientIdentityInfo":
+		v := value.(readfields.ClientIdentityInfo)
+		WriteFields(w, v.UUID, uint32(len(v.Identities)))
+		for _, id := range v.Identities {
+			WriteFields(w, id.Type, uint32(len(id.Data)), id.Data)
+		}
+	default:
+		WriteAllProperties(w, value.([]saveformat.Property))
+	}
+}
@@ -0,0 +1,80 @@
+package writefields
+
+import (
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func WriteFields(w io.Writer, fields ...any) {
+	for _, field := range fields {
+		switch field := field.(type) {
+		case string:
+			if err := writeString(w, field); err != nil {
+				panic("Error writing string field: " + err.Error())
+			}
+		case saveformat.ObjectReference:
+			WriteFields(w, field.LevelName, field.PathName)
+		case readfields.ArraySoftObjectProperty:
+			WriteFields(w, field.Reference, field.Value)
+		case nil:
+			continue
+		case []any:
+			WriteFields(w, field...)
+		default:
+			err := binary.Write(w, binary.LittleEndian, field)
+			if err != nil {
+				panic("Error writing field: " + err.Error())
+			}
+		}
+	}
+}
+
+func isPureAscii(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// stringEncoder is implemented by writers that keep the encoding strings were read with.
+type stringEncoder interface {
+	StringEncoding(s string) (saveformat.StringEncoding, bool)
+}
+
+func writeString(w io.Writer, s string) error {
+	if s == "" {
+		return binary.Write(w, binary.LittleEndian, int32(0))
+	}
+
+	encoding := saveformat.EncodingUTF16
+	if isPureAscii(s) {
+		encoding = saveformat.EncodingUTF8
+	}
+	if e, ok := w.(stringEncoder); ok {
+		if read, ok := e.StringEncoding(s); ok {
+			encoding = read
+		}
+	}
+
+	if encoding == saveformat.EncodingUTF8 {
+		// UTF-8 string with null terminator
+		if err := binary.Write(w, binary.LittleEndian, int32(len(s)+1)); err != nil {
+			return err
+		}
+		_, err := w.Write(append([]byte(s), 0))
+		return err
+	}
+
+	// UTF-16 LE string with null terminator
+	utf16Data := append(utf16.Encode([]rune(s)), 0)
+	if err := binary.Write(w, binary.LittleEndian, -int32(len(utf16Data))); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, utf16Data)
+}
@@ -0,0 +1,125 @@
+package writesave
+
+import (
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/trackingwriter"
+	. "github.com/Maurits825/satisfactory-savefile-parser/internal/writesave/writefields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func writeLevelData(w *trackingwriter.TrackingWriter, levelData saveformat.LevelData, version uint32, isPersistentLevel bool) {
+	if isPersistentLevel {
+		defer w.Leave(w.Enter("Persistent_Level"))
+	} else {
+		defer w.Leave(w.Enter(levelData.Name))
+		WriteFields(w, levelData.Name)
+	}
+
+	sizePos := w.Reserve(8)
+	start := w.Position()
+	WriteFields(w, uint32(len(levelData.HeaderTypes)))
+	writeLevelHeader(w, levelData, version)
+
+	if levelData.HasCollectables {
+		if isPersistentLevel && levelData.CollectablesLevelCount > 0 {
+			WriteFields(w, levelData.CollectablesLevelCount, levelData.CollectablesLevelName)
+		}
+		writeObjectReferences(w, levelData.Collectables)
+	}
+	w.PatchUint64(sizePos, uint64(w.Position()-start))
+
+	sizePos = w.Reserve(8)
+	start = w.Position()
+	WriteFields(w, uint32(len(levelData.HeaderTypes)))
+
+	actorIndex, componentIndex := 0, 0
+	for _, headerType := range levelData.HeaderTypes {
+		if headerType == 0 {
+			writeComponentObject(w, levelData.ComponentHeaders[componentIndex], levelData.ComponentObjects[componentIndex])
+			componentIndex++
+		} else {
+			writeActorObject(w, levelData.ActorHeaders[actorIndex], levelData.ActorObjects[actorIndex])
+			actorIndex++
+		}
+	}
+	w.PatchUint64(sizePos, uint64(w.Position()-start))
+
+	if !isPersistentLevel && version >= 51 {
+		WriteFields(w, levelData.SaveVersion)
+	}
+
+	writeObjectReferences(w, levelData.SecondCollectables)
+}
+
+func writeObjectReferences(w *trackingwriter.TrackingWriter, references []saveformat.ObjectReference) {
+	WriteFields(w, uint32(len(references)))
+	for _, reference := range references {
+		WriteFields(w, reference)
+	}
+}
+
+func writeLevelHeader(w *trackingwriter.TrackingWriter, levelData saveformat.LevelData, version uint32) {
+	actorIndex, componentIndex := 0, 0
+	for _, headerType := range levelData.HeaderTypes {
+		WriteFields(w, headerType)
+		if headerType == 0 {
+			componentHeader := levelData.ComponentHeaders[componentIndex]
+			componentIndex++
+			start := w.Enter(componentHeader.Name)
+			WriteFields(w,
+				componentHeader.TypePath, componentHeader.Root,
+				componentHeader.Name, readfields.ConditionalFields(version >= 51, componentHeader.Flags),
+				componentHeader.ParentActorName,
+			)
+			w.Leave(start)
+		} else {
+			actorHeader := levelData.ActorHeaders[actorIndex]
+			actorIndex++
+			start := w.Enter(actorHeader.Name)
+			WriteFields(w,
+				actorHeader.TypePath, actorHeader.Root, actorHeader.Name,
+				readfields.ConditionalFields(version >= 51, actorHeader.Flags), actorHeader.NeedTransform,
+				actorHeader.RotationX, actorHeader.RotationY, actorHeader.RotationZ, actorHeader.RotationW,
+				actorHeader.PositionX, actorHeader.PositionY, actorHeader.PositionZ,
+				actorHeader.ScaleX, actorHeader.ScaleY, actorHeader.ScaleZ,
+				actorHeader.WasPlaced,
+			)
+			w.Leave(start)
+		}
+	}
+}
+
+func writeComponentObject(w *trackingwriter.TrackingWriter, header saveformat.ComponentHeader, component saveformat.ComponentObject) {
+	defer w.Leave(w.Enter(header.Name))
+
+	WriteFields(w, component.SaveVersion, component.Flag)
+	sizePos := w.Reserve(4)
+	start := w.Position()
+	writeProperties(w, component.Properties, component.Trailing, component.Lazy)
+	w.PatchUint32(sizePos, uint32(w.Position()-start))
+}
+
+func writeActorObject(w *trackingwriter.TrackingWriter, header saveformat.ActorHeader, actor saveformat.ActorObject) {
+	defer w.Leave(w.Enter(header.Name))
+
+	WriteFields(w, actor.SaveVersion, actor.Flag)
+	sizePos := w.Reserve(4)
+	start := w.Position()
+	WriteFields(w, actor.ParentReference, uint32(len(actor.Components)))
+	for _, component := range actor.Components {
+		WriteFields(w, component)
+	}
+	writeProperties(w, actor.Properties, actor.Trailing, actor.Lazy)
+	w.PatchUint32(sizePos, uint32(w.Position()-start))
+}
+
+// writeProperties writes the properties and trailing bytes of an object, or its raw block when it was never decoded.
+func writeProperties(w *trackingwriter.TrackingWriter, props []saveformat.Property, trailing []byte, lazy *saveformat.LazyProperties) {
+	if lazy != nil {
+		WriteFields(w, lazy.Data)
+		return
+	}
+
+	WriteAllProperties(w, props)
+	WriteFields(w, trailing)
+}
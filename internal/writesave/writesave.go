@@ -0,0 +1,21 @@
+package writesave
+
+import (
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/trackingwriter"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// WriteBody serializes the save file body to its uncompressed binary form.
+func WriteBody(body *saveformat.SaveFileBody, version uint32) []byte {
+	w := trackingwriter.NewTrackingWriter(-1)
+	writeSaveFileBody(w, body, version)
+	return w.Bytes()
+}
+
+// PathAt serializes the body again and returns the path (level, object, property, ...)
+// of the innermost value written at offset.
+func PathAt(body *saveformat.SaveFileBody, version uint32, offset int64) []string {
+	w := trackingwriter.NewTrackingWriter(offset)
+	writeSaveFileBody(w, body, version)
+	return w.FoundPath()
+}
@@ -1,13 +1,103 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/spatial"
 )
 
 func main() {
-	saveFile := "pkg/parser/testdata/test_benchmark.sav"
-	body := parser.ParseSaveFile(saveFile)
+	saveFile := flag.String("save", "", "save file to parse (required)")
+	near := flag.String("near", "", "find actors near the point x,y,z, in centimeters")
+	radius := flag.Float64("radius", 0, "with -near, find all actors within this many centimeters")
+	nearest := flag.Int("k", 10, "with -near and without -radius, the number of nearest actors")
+	within := flag.String("within", "", "find actors inside the box minX,minY,minZ,maxX,maxY,maxZ")
+	classes := flag.String("class", "", "comma separated classes to find, e.g. Build_ConstructorMk1_C")
+	flag.Parse()
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	switch {
+	case *saveFile == "":
+		usageError("-save is required")
+	case (set["radius"] || set["k"]) && *near == "":
+		usageError("-radius and -k require -near")
+	case set["radius"] && set["k"]:
+		usageError("-radius and -k can not be combined")
+	case *near != "" && *within != "":
+		usageError("-near and -within can not be combined")
+	case *radius < 0 || *nearest < 1:
+		usageError("-radius must be positive and -k at least 1")
+	}
+
+	body := parser.ParseSaveFile(*saveFile)
+	if body == nil {
+		os.Exit(1)
+	}
 	fmt.Println("Save file parsed successfully!", body.UncompressedSize)
+	if *near == "" && *within == "" {
+		return
+	}
+
+	var filters []spatial.Filter
+	if *classes != "" {
+		filters = append(filters, spatial.Classes(strings.Split(*classes, ",")...))
+	}
+	index := spatial.NewIndex(saveformat.NewSaveIndex(body))
+
+	var results []spatial.Result
+	switch {
+	case *within != "":
+		values := parseFloats(*within, 6)
+		box := geometry.AABB{
+			Min: geometry.Vector{X: values[0], Y: values[1], Z: values[2]},
+			Max: geometry.Vector{X: values[3], Y: values[4], Z: values[5]},
+		}
+		results = index.Within(box, filters...)
+	case *radius > 0:
+		values := parseFloats(*near, 3)
+		results = index.InRadius(geometry.Vector{X: values[0], Y: values[1], Z: values[2]}, *radius, filters...)
+	default:
+		values := parseFloats(*near, 3)
+		results = index.Nearest(geometry.Vector{X: values[0], Y: values[1], Z: values[2]}, *nearest, filters...)
+	}
+
+	for _, result := range results {
+		p := result.Position
+		fmt.Printf("%-40s %12.0f %12.0f %12.0f %10.0f  %s\n",
+			model.ClassName(result.Actor.Header.TypePath), p.X, p.Y, p.Z, result.Distance, result.Actor.Header.Name)
+	}
+	fmt.Println(len(results), "actors found")
+}
+
+func usageError(message string) {
+	fmt.Fprintln(flag.CommandLine.Output(), "Error:", message)
+	flag.Usage()
+	os.Exit(2)
+}
+
+func parseFloats(s string, n int) []float64 {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		fmt.Printf("Error: %q must have %d comma separated numbers\n", s, n)
+		os.Exit(2)
+	}
+	values := make([]float64, n)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(2)
+		}
+		values[i] = value
+	}
+	return values
 }
@@ -0,0 +1,157 @@
+// Package collectibles reports which world collectibles of a save are collected and where the others are.
+package collectibles
+
+import (
+	_ "embed"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// collectibles.json are the world placements of the collectibles, written by gencollectibles.go from
+// an export of the map actors.
+//
+//go:generate go run gencollectibles.go -out collectibles.json ${SATISFACTORY_MAP_EXPORT}
+//go:embed collectibles.json
+var collectiblesJSON []byte
+
+type Kind string
+
+const (
+	KindHardDrive       Kind = "hardDrive"
+	KindMercerSphere    Kind = "mercerSphere"
+	KindPowerSlugBlue   Kind = "powerSlugBlue"
+	KindPowerSlugPurple Kind = "powerSlugPurple"
+	KindPowerSlugYellow Kind = "powerSlugYellow"
+	KindSomersloop      Kind = "somersloop"
+)
+
+var kinds = map[string]Kind{
+	"BP_DropPod_C":     KindHardDrive,
+	"BP_WAT2_C":        KindMercerSphere,
+	"BP_Crystal_C":     KindPowerSlugBlue,
+	"BP_Crystal_mk3_C": KindPowerSlugPurple,
+	"BP_Crystal_mk2_C": KindPowerSlugYellow,
+	"BP_WAT1_C":        KindSomersloop,
+}
+
+// Collectible is a collectible placed in the world. Hard drives are collected by opening their drop pod.
+type Collectible struct {
+	Kind      Kind    `json:"kind"`
+	PathName  string  `json:"pathName"`
+	X         float32 `json:"x"`
+	Y         float32 `json:"y"`
+	Z         float32 `json:"z"`
+	Collected bool    `json:"-"`
+}
+
+// known are the world placements of the embedded table.
+var known []Collectible
+
+func init() {
+	var table struct {
+		Collectibles []Collectible `json:"collectibles"`
+	}
+	if err := json.Unmarshal(collectiblesJSON, &table); err != nil {
+		panic("parsing embedded collectibles: " + err.Error())
+	}
+	known = table.Collectibles
+}
+
+// Progress is the collected count of one kind.
+type Progress struct {
+	Kind        Kind
+	Collected   int
+	Total       int
+	Uncollected []*Collectible
+}
+
+type Report struct {
+	Collectibles []*Collectible // sorted by kind and path name
+	Progress     []Progress     // sorted by kind
+}
+
+// NewReport marks the collectibles of the embedded table and of the save as collected or not.
+// Collectibles are collected when the save lists them as destroyed, and hard drives when their
+// drop pod is opened. Collectibles in the save but missing from the table are added, collected
+// ones without a position in the table are reported at the origin.
+func NewReport(save *saveformat.SaveIndex) (*Report, error) {
+	byPath := make(map[string]*Collectible)
+	add := func(c Collectible) *Collectible {
+		if byPath[c.PathName] == nil {
+			byPath[c.PathName] = &c
+		}
+		return byPath[c.PathName]
+	}
+	for _, c := range known {
+		add(c)
+	}
+
+	for _, ref := range save.Collected {
+		if kind, ok := kinds[className(ref.PathName)]; ok {
+			add(Collectible{Kind: kind, PathName: ref.PathName}).Collected = true
+		}
+	}
+
+	for _, actor := range save.Actors {
+		kind, ok := kinds[model.ClassName(actor.Header.TypePath)]
+		if !ok {
+			continue
+		}
+		c := add(Collectible{
+			Kind:     kind,
+			PathName: actor.Header.Name,
+			X:        actor.Header.PositionX,
+			Y:        actor.Header.PositionY,
+			Z:        actor.Header.PositionZ,
+		})
+		if kind == KindHardDrive {
+			props, err := actor.DecodedProperties()
+			if err != nil {
+				return nil, err
+			}
+			c.Collected, _ = props.Bool("mHasBeenOpened")
+		}
+	}
+
+	var report Report
+	for _, c := range byPath {
+		report.Collectibles = append(report.Collectibles, c)
+	}
+	slices.SortFunc(report.Collectibles, func(a, b *Collectible) int {
+		if a.Kind != b.Kind {
+			return strings.Compare(string(a.Kind), string(b.Kind))
+		}
+		return strings.Compare(a.PathName, b.PathName)
+	})
+
+	for _, c := range report.Collectibles {
+		if len(report.Progress) == 0 || report.Progress[len(report.Progress)-1].Kind != c.Kind {
+			report.Progress = append(report.Progress, Progress{Kind: c.Kind})
+		}
+		progress := &report.Progress[len(report.Progress)-1]
+		progress.Total++
+		if c.Collected {
+			progress.Collected++
+		} else {
+			progress.Uncollected = append(progress.Uncollected, c)
+		}
+	}
+	return &report, nil
+}
+
+// className guesses the class of a destroyed level actor from its path name, since only the
+// path is saved, e.g. BP_WAT11_1 is a BP_WAT1_C.
+func className(pathName string) string {
+	name := pathName[strings.LastIndex(pathName, ".")+1:]
+	for class := range kinds {
+		prefix := strings.TrimSuffix(class, "_C")
+		if strings.HasPrefix(name, prefix) && !strings.HasPrefix(name[len(prefix):], "_mk") {
+			return class
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package collectibles_test
+
+import (
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/collectibles"
+)
+
+func TestReport(t *testing.T) {
+	report, err := collectibles.NewReport(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// collected and placed in the test save, the totals include the world placements of the table
+	want := map[collectibles.Kind][2]int{
+		collectibles.KindHardDrive:       {0, 31},
+		collectibles.KindMercerSphere:    {0, 16},
+		collectibles.KindPowerSlugBlue:   {0, 42},
+		collectibles.KindPowerSlugPurple: {0, 5},
+		collectibles.KindPowerSlugYellow: {0, 14},
+		collectibles.KindSomersloop:      {5, 6},
+	}
+	if len(report.Progress) != len(want) {
+		t.Fatal("Progress kinds:", len(report.Progress))
+	}
+	for _, progress := range report.Progress {
+		if w := want[progress.Kind]; progress.Collected != w[0] || progress.Total < w[1] || len(progress.Uncollected) != progress.Total-w[0] {
+			t.Error("Progress of", progress.Kind, progress.Collected, "/", progress.Total, "want", w[0], "/", w[1])
+		}
+		for _, c := range progress.Uncollected {
+			if c.Collected || c.X == 0 && c.Y == 0 {
+				t.Error("Invalid uncollected:", c.PathName, c.X, c.Y)
+			}
+		}
+	}
+}
+
+func TestWorldTotals(t *testing.T) {
+	report, err := collectibles.NewReport(new(testsave.Level).Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Collectibles) == 0 {
+		t.Skip("collectibles.json is empty, generate it from a map export")
+	}
+
+	// collectibles placed in the 1.0 world
+	want := map[collectibles.Kind]int{
+		collectibles.KindHardDrive:       118,
+		collectibles.KindMercerSphere:    298,
+		collectibles.KindPowerSlugBlue:   596,
+		collectibles.KindPowerSlugPurple: 257,
+		collectibles.KindPowerSlugYellow: 389,
+		collectibles.KindSomersloop:      106,
+	}
+	if len(report.Progress) != len(want) {
+		t.Fatal("Progress kinds:", len(report.Progress))
+	}
+	for _, progress := range report.Progress {
+		if progress.Total != want[progress.Kind] || progress.Collected != 0 {
+			t.Error("Total of", progress.Kind, progress.Total, "want", want[progress.Kind])
+		}
+	}
+}
+
+func TestReportCollectedClasses(t *testing.T) {
+	// only the path names of destroyed actors are saved, their class is guessed from the name
+	kinds := map[string]collectibles.Kind{
+		"Persistent_Level:PersistentLevel.BP_WAT11_1":                         collectibles.KindSomersloop,
+		"Persistent_Level:PersistentLevel.BP_WAT21_UAID_40B076DF2F79CA7C01_1": collectibles.KindMercerSphere,
+		"Persistent_Level:PersistentLevel.BP_Crystal16_16":                    collectibles.KindPowerSlugBlue,
+		"Persistent_Level:PersistentLevel.BP_Crystal_mk27_6":                  collectibles.KindPowerSlugYellow,
+		"Persistent_Level:PersistentLevel.BP_Crystal_mk34_3":                  collectibles.KindPowerSlugPurple,
+		"Persistent_Level:PersistentLevel.BP_BerryBush743_913":                "",
+	}
+	var level testsave.Level
+	for pathName := range kinds {
+		level.Collectables = append(level.Collectables, testsave.Ref(pathName))
+	}
+
+	report, err := collectibles.NewReport(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]collectibles.Kind)
+	for _, c := range report.Collectibles {
+		if c.Collected {
+			found[c.PathName] = c.Kind
+		}
+	}
+	for pathName, kind := range kinds {
+		if found[pathName] != kind {
+			t.Error("Kind of", pathName, found[pathName], "want", kind)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+//go:build ignore
+
+// Gencollectibles writes collectibles.json from JSON exports of the map levels, as written by
+// FModel for /Game/FactoryGame/Map/GameLevel01/Persistent_Level and its world partition cells.
+// Arguments are export files or directories searched for .json files.
+//
+//	go run gencollectibles.go -out collectibles.json "$SATISFACTORY_MAP_EXPORT"
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var kinds = map[string]string{
+	"BP_DropPod_C":     "hardDrive",
+	"BP_WAT2_C":        "mercerSphere",
+	"BP_Crystal_C":     "powerSlugBlue",
+	"BP_Crystal_mk3_C": "powerSlugPurple",
+	"BP_Crystal_mk2_C": "powerSlugYellow",
+	"BP_WAT1_C":        "somersloop",
+}
+
+type vector struct{ X, Y, Z float64 }
+
+type export struct {
+	Type       string
+	Name       string
+	Outer      string
+	Properties struct {
+		RootComponent    *struct{ ObjectName string }
+		RelativeLocation *vector
+	}
+}
+
+type collectible struct {
+	Kind     string  `json:"kind"`
+	PathName string  `json:"pathName"`
+	X        float32 `json:"x"`
+	Y        float32 `json:"y"`
+	Z        float32 `json:"z"`
+}
+
+func main() {
+	outPath := flag.String("out", "collectibles.json", "output file")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("no map exports given")
+	}
+
+	byPath := make(map[string]collectible)
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".json") {
+				return err
+			}
+			return readExports(path, byPath)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	collectibles := make([]collectible, 0, len(byPath))
+	for _, c := range byPath {
+		collectibles = append(collectibles, c)
+	}
+	slices.SortFunc(collectibles, func(a, b collectible) int {
+		if a.Kind != b.Kind {
+			return strings.Compare(a.Kind, b.Kind)
+		}
+		return strings.Compare(a.PathName, b.PathName)
+	})
+
+	var out bytes.Buffer
+	out.WriteString("{\n\t\"collectibles\": [\n")
+	for i, c := range collectibles {
+		fmt.Fprintf(&out, "\t\t{\"kind\": %q, \"pathName\": %q, \"x\": %v, \"y\": %v, \"z\": %v}", c.Kind, c.PathName, c.X, c.Y, c.Z)
+		if i < len(collectibles)-1 {
+			out.WriteByte(',')
+		}
+		out.WriteByte('\n')
+	}
+	out.WriteString("\t]\n}\n")
+	if err := os.WriteFile(*outPath, out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(collectibles), "collectibles")
+}
+
+// readExports adds the collectibles of one level export. The position of an actor is the
+// relative location of its root component, which is a separate export with the actor as outer.
+func readExports(path string, byPath map[string]collectible) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var exports []export
+	if err := json.Unmarshal(data, &exports); err != nil {
+		// not a level export
+		return nil
+	}
+
+	locations := make(map[string]vector)
+	for _, e := range exports {
+		if e.Properties.RelativeLocation != nil {
+			locations[e.Outer+"."+e.Name] = *e.Properties.RelativeLocation
+		}
+	}
+	for _, e := range exports {
+		kind, ok := kinds[e.Type]
+		if !ok {
+			continue
+		}
+		if e.Properties.RootComponent == nil {
+			return fmt.Errorf("%s: %s has no root component", path, e.Name)
+		}
+		root := e.Properties.RootComponent.ObjectName
+		root = strings.TrimSuffix(root[strings.LastIndexAny(root, ".:")+1:], "'")
+		location, ok := locations[e.Name+"."+root]
+		if !ok {
+			return fmt.Errorf("%s: %s has no location", path, e.Name)
+		}
+		pathName := "Persistent_Level:PersistentLevel." + e.Name
+		byPath[pathName] = collectible{
+			Kind:     kind,
+			PathName: pathName,
+			X:        float32(location.X),
+			Y:        float32(location.Y),
+			Z:        float32(location.Z),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Package geometry converts the positions, rotations and bounds of a save to world and map
+// coordinates. World coordinates are Unreal units, centimeters with X east, Y south and Z up.
+package geometry
+
+import (
+	"math"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// Vector is a world position or direction. A decoded Vector property converts with Vector(v).
+type Vector struct {
+	X, Y, Z float64
+}
+
+func (v Vector) Add(o Vector) Vector {
+	return Vector{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+func (v Vector) Sub(o Vector) Vector {
+	return Vector{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+// Mul multiplies the components, e.g. to apply a scale.
+func (v Vector) Mul(o Vector) Vector {
+	return Vector{v.X * o.X, v.Y * o.Y, v.Z * o.Z}
+}
+
+func (v Vector) Scale(s float64) Vector {
+	return Vector{v.X * s, v.Y * s, v.Z * s}
+}
+
+func (v Vector) Length() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+func (v Vector) Distance(o Vector) float64 {
+	return v.Sub(o).Length()
+}
+
+// Distance2D is the distance on the map, ignoring the height.
+func (v Vector) Distance2D(o Vector) float64 {
+	return math.Hypot(v.X-o.X, v.Y-o.Y)
+}
+
+func cross(a, b Vector) Vector {
+	return Vector{a.Y*b.Z - a.Z*b.Y, a.Z*b.X - a.X*b.Z, a.X*b.Y - a.Y*b.X}
+}
+
+// Quat is a rotation quaternion. A decoded Quat property converts with Quat(q).
+type Quat struct {
+	X, Y, Z, W float64
+}
+
+var Identity = Quat{W: 1}
+
+// Rotator is a rotation in Euler angles, in degrees as in Unreal.
+type Rotator struct {
+	Pitch float64 // around Y
+	Yaw   float64 // around Z, 0 faces east
+	Roll  float64 // around X
+}
+
+// Euler converts the quaternion to Euler angles like Unreal's FQuat::Rotator.
+func (q Quat) Euler() Rotator {
+	const threshold = 0.4999995
+	singularity := q.Z*q.X - q.W*q.Y
+	yaw := degrees(math.Atan2(2*(q.W*q.Z+q.X*q.Y), 1-2*(q.Y*q.Y+q.Z*q.Z)))
+
+	switch {
+	case singularity < -threshold:
+		return Rotator{Pitch: -90, Yaw: yaw, Roll: normalizeAxis(-yaw - 2*degrees(math.Atan2(q.X, q.W)))}
+	case singularity > threshold:
+		return Rotator{Pitch: 90, Yaw: yaw, Roll: normalizeAxis(yaw - 2*degrees(math.Atan2(q.X, q.W)))}
+	}
+	return Rotator{
+		Pitch: degrees(math.Asin(2 * singularity)),
+		Yaw:   yaw,
+		Roll:  degrees(math.Atan2(-2*(q.W*q.X+q.Y*q.Z), 1-2*(q.X*q.X+q.Y*q.Y))),
+	}
+}
+
+// Quat converts the Euler angles to a quaternion like Unreal's FRotator::Quaternion.
+func (r Rotator) Quat() Quat {
+	sp, cp := math.Sincos(radians(r.Pitch) / 2)
+	sy, cy := math.Sincos(radians(r.Yaw) / 2)
+	sr, cr := math.Sincos(radians(r.Roll) / 2)
+	return Quat{
+		X: cr*sp*sy - sr*cp*cy,
+		Y: -cr*sp*cy - sr*cp*sy,
+		Z: cr*cp*sy - sr*sp*cy,
+		W: cr*cp*cy + sr*sp*sy,
+	}
+}
+
+// Rotate rotates a vector by the quaternion, which must be normalized.
+func (q Quat) Rotate(v Vector) Vector {
+	axis := Vector{q.X, q.Y, q.Z}
+	t := cross(axis, v).Scale(2)
+	return v.Add(t.Scale(q.W)).Add(cross(axis, t))
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// normalizeAxis wraps an angle to (-180, 180].
+func normalizeAxis(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg > 180 {
+		deg -= 360
+	} else if deg <= -180 {
+		deg += 360
+	}
+	return deg
+}
+
+// Transform places an actor in the world.
+type Transform struct {
+	position Vector
+	rotation Quat
+	scale    Vector
+}
+
+func NewTransform(position Vector, rotation Quat, scale Vector) Transform {
+	return Transform{position: position, rotation: rotation, scale: scale}
+}
+
+// ActorTransform returns the transform saved in an actor header.
+func ActorTransform(header *saveformat.ActorHeader) Transform {
+	return Transform{
+		position: Vector{float64(header.PositionX), float64(header.PositionY), float64(header.PositionZ)},
+		rotation: Quat{float64(header.RotationX), float64(header.RotationY), float64(header.RotationZ), float64(header.RotationW)},
+		scale:    Vector{float64(header.ScaleX), float64(header.ScaleY), float64(header.ScaleZ)},
+	}
+}
+
+func (t Transform) Position() Vector {
+	return t.position
+}
+
+func (t Transform) Rotation() Quat {
+	return t.rotation
+}
+
+func (t Transform) Scale() Vector {
+	return t.scale
+}
+
+// Apply converts a position relative to the actor to a world position.
+func (t Transform) Apply(local Vector) Vector {
+	return t.position.Add(t.rotation.Rotate(local.Mul(t.scale)))
+}
+
+// AABB is an axis aligned bounding box.
+type AABB struct {
+	Min, Max Vector
+}
+
+// BoxAABB converts a decoded Box property, which is false when the box is not valid.
+func BoxAABB(box readfields.Box) (AABB, bool) {
+	return AABB{
+		Min: Vector{box.MinX, box.MinY, box.MinZ},
+		Max: Vector{box.MaxX, box.MaxY, box.MaxZ},
+	}, box.IsValid != 0
+}
+
+func (b AABB) Center() Vector {
+	return b.Min.Add(b.Max).Scale(0.5)
+}
+
+func (b AABB) Size() Vector {
+	return b.Max.Sub(b.Min)
+}
+
+func (b AABB) Contains(v Vector) bool {
+	return v.X >= b.Min.X && v.X <= b.Max.X && v.Y >= b.Min.Y && v.Y <= b.Max.Y && v.Z >= b.Min.Z && v.Z <= b.Max.Z
+}
+
+func (b AABB) Intersects(o AABB) bool {
+	return b.Min.X <= o.Max.X && b.Max.X >= o.Min.X &&
+		b.Min.Y <= o.Max.Y && b.Max.Y >= o.Min.Y &&
+		b.Min.Z <= o.Max.Z && b.Max.Z >= o.Min.Z
+}
+
+// Extend returns the box grown to contain v.
+func (b AABB) Extend(v Vector) AABB {
+	return AABB{
+		Min: Vector{min(b.Min.X, v.X), min(b.Min.Y, v.Y), min(b.Min.Z, v.Z)},
+		Max: Vector{max(b.Max.X, v.X), max(b.Max.Y, v.Y), max(b.Max.Z, v.Z)},
+	}
+}
+
+// MapBounds is the world area shown by the in-game map, the Z range is unused.
+var MapBounds = AABB{
+	Min: Vector{X: -324698.832031250, Y: -375000},
+	Max: Vector{X: 425301.832031250, Y: 375000},
+}
+
+// MapProjection projects world positions onto a map image of Width by Height pixels covering
+// Bounds, with the origin at the top left (north west) corner.
+type MapProjection struct {
+	Bounds        AABB
+	Width, Height float64
+}
+
+// NewMapProjection returns the projection onto a size by size pixel image of the in-game map.
+func NewMapProjection(size float64) MapProjection {
+	return MapProjection{Bounds: MapBounds, Width: size, Height: size}
+}
+
+// normalize returns the position on the map in [0, 1], outside that range when off the map.
+func (p MapProjection) normalize(v Vector) (u, w float64) {
+	size := p.Bounds.Size()
+	return (v.X - p.Bounds.Min.X) / size.X, (v.Y - p.Bounds.Min.Y) / size.Y
+}
+
+func (p MapProjection) Pixel(v Vector) (x, y float64) {
+	u, w := p.normalize(v)
+	return u * p.Width, w * p.Height
+}
+
+// World is the inverse of Pixel, at height zero.
+func (p MapProjection) World(x, y float64) Vector {
+	size := p.Bounds.Size()
+	return Vector{X: p.Bounds.Min.X + x/p.Width*size.X, Y: p.Bounds.Min.Y + y/p.Height*size.Y}
+}
+
+// Tile returns the tile containing v in a tile pyramid where zoom level z splits the map into
+// 2^z by 2^z tiles. Positions off the map return tiles outside that range.
+func (p MapProjection) Tile(v Vector, zoom int) (x, y int) {
+	u, w := p.normalize(v)
+	tiles := float64(int(1) << zoom)
+	return int(math.Floor(u * tiles)), int(math.Floor(w * tiles))
+}
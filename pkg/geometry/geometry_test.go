@@ -0,0 +1,80 @@
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func near(a, b geometry.Vector) bool {
+	return a.Distance(b) < 1e-6
+}
+
+func TestEuler(t *testing.T) {
+	for _, r := range []geometry.Rotator{{}, {Yaw: 90}, {Pitch: 30, Yaw: -45, Roll: 10}, {Pitch: 90, Yaw: 20}} {
+		// at a pitch of 90 only the sum of yaw and roll is defined, so compare the rotations
+		got := r.Quat().Euler()
+		v := geometry.Vector{X: 1, Y: 2, Z: 3}
+		if !near(got.Quat().Rotate(v), r.Quat().Rotate(v)) || math.Abs(got.Pitch-r.Pitch) > 1e-6 {
+			t.Error("Euler round trip:", r, got)
+		}
+	}
+
+	// yaw turns east towards south
+	if v := (geometry.Rotator{Yaw: 90}).Quat().Rotate(geometry.Vector{X: 1}); !near(v, geometry.Vector{Y: 1}) {
+		t.Error("Rotate:", v)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	quat := geometry.Rotator{Yaw: 180}.Quat()
+	header := saveformat.ActorHeader{
+		PositionX: 100, PositionY: 200, PositionZ: 300,
+		RotationX: float32(quat.X), RotationY: float32(quat.Y), RotationZ: float32(quat.Z), RotationW: float32(quat.W),
+		ScaleX: 2, ScaleY: 1, ScaleZ: 1,
+	}
+	transform := geometry.ActorTransform(&header)
+	if transform.Position() != (geometry.Vector{X: 100, Y: 200, Z: 300}) || math.Abs(transform.Rotation().Euler().Yaw) != 180 {
+		t.Error("Invalid transform:", transform)
+	}
+	if v := transform.Apply(geometry.Vector{X: 10, Z: 5}); !near(v, geometry.Vector{X: 80, Y: 200, Z: 305}) {
+		t.Error("Apply:", v)
+	}
+	if d := transform.Position().Distance2D(geometry.Vector{X: 400, Y: 600}); d != 500 {
+		t.Error("Distance2D:", d)
+	}
+}
+
+func TestAABB(t *testing.T) {
+	box, ok := geometry.BoxAABB(readfields.Box{MinX: -1, MinY: -2, MinZ: -3, MaxX: 1, MaxY: 2, MaxZ: 3, IsValid: 1})
+	if !ok || box.Center() != (geometry.Vector{}) || box.Size() != (geometry.Vector{X: 2, Y: 4, Z: 6}) {
+		t.Error("Invalid box:", box, ok)
+	}
+	if !box.Contains(geometry.Vector{X: 1, Y: -2}) || box.Contains(geometry.Vector{Z: 4}) {
+		t.Error("Contains")
+	}
+	other := (geometry.AABB{}).Extend(geometry.Vector{X: 5, Y: 5, Z: 5})
+	if !box.Intersects(other) || box.Intersects(geometry.AABB{Min: geometry.Vector{X: 2}, Max: other.Max}) {
+		t.Error("Intersects:", other)
+	}
+}
+
+func TestMapProjection(t *testing.T) {
+	projection := geometry.NewMapProjection(1000)
+	if x, y := projection.Pixel(geometry.MapBounds.Min); x != 0 || y != 0 {
+		t.Error("Pixel of north west corner:", x, y)
+	}
+	if x, y := projection.Pixel(geometry.MapBounds.Max); x != 1000 || y != 1000 {
+		t.Error("Pixel of south east corner:", x, y)
+	}
+	center := geometry.MapBounds.Center()
+	if v := projection.World(projection.Pixel(center)); !near(v, center) {
+		t.Error("World:", v)
+	}
+	if x, y := projection.Tile(center.Add(geometry.Vector{X: -1, Y: 1}), 3); x != 3 || y != 4 {
+		t.Error("Tile:", x, y)
+	}
+}
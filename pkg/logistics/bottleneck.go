@@ -0,0 +1,85 @@
+package logistics
+
+import (
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/production"
+)
+
+// Bottleneck is an output of a production building whose belts or pipes can not carry its product.
+type Bottleneck struct {
+	Producer   *Node
+	Output     *Connection
+	Item       string
+	Rate       float64 // expected items or m³ per minute of the producer
+	Limit      *Node   // slowest belt or pipe after the output
+	Throughput float64
+}
+
+// Bottlenecks compares the expected product rates of the buildings with the belts and pipes
+// carrying them to the next building. Solid products are matched to the conveyor outputs and
+// fluid products to the pipe outputs, in recipe and output name order.
+func (graph *Graph) Bottlenecks(buildings []*model.ProductionBuilding) []Bottleneck {
+	var bottlenecks []Bottleneck
+	for _, building := range buildings {
+		node := graph.Node(building.Actor)
+		recipe, ok := production.LookupRecipe(building.Recipe)
+		if node == nil || !ok {
+			continue
+		}
+
+		conveyors, pipes := node.outputs(false), node.outputs(true)
+		for _, product := range production.Rate(building, recipe).Produced {
+			item, _ := production.LookupItem(product.Item)
+			outputs := &conveyors
+			if item != nil && item.Fluid {
+				outputs = &pipes
+			}
+			if len(*outputs) == 0 {
+				continue
+			}
+			output := (*outputs)[0]
+			*outputs = (*outputs)[1:]
+
+			limit := slowestTransport(output)
+			if limit != nil && product.Amount > limit.Throughput {
+				bottlenecks = append(bottlenecks, Bottleneck{
+					Producer:   node,
+					Output:     output,
+					Item:       product.Item,
+					Rate:       product.Amount,
+					Limit:      limit,
+					Throughput: limit.Throughput,
+				})
+			}
+		}
+	}
+	return bottlenecks
+}
+
+// slowestTransport follows the belts or pipes after an output up to the next node that is not
+// a belt or pipe, and returns the slowest one.
+func slowestTransport(output *Connection) *Node {
+	var slowest *Node
+	visited := make(map[*Node]bool)
+	connection := output
+	for connection != nil && connection.Edge != nil {
+		node := other(connection.Edge, connection).Node
+		if !node.IsTransport() || visited[node] {
+			break
+		}
+		visited[node] = true
+		if slowest == nil || node.Throughput < slowest.Throughput {
+			slowest = node
+		}
+
+		entry := other(connection.Edge, connection)
+		connection = nil
+		for _, next := range node.Connections {
+			if next != entry && next.Direction != DirectionInput {
+				connection = next
+				break
+			}
+		}
+	}
+	return slowest
+}
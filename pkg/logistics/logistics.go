@@ -0,0 +1,270 @@
+// Package logistics links buildings, belts and pipes into a graph by their factory and pipe connections.
+package logistics
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const (
+	factoryConnectionType = "/Script/FactoryGame.FGFactoryConnectionComponent"
+	pipeConnectionPrefix  = "/Script/FactoryGame.FGPipeConnection"
+)
+
+// Direction is the flow direction of a connection. The direction is not saved, it is
+// derived from the connection name.
+type Direction int
+
+const (
+	DirectionAny Direction = iota // pipeline ends, which flow either way
+	DirectionInput
+	DirectionOutput
+)
+
+// Connection is a factory or pipe connection component of a node.
+type Connection struct {
+	Component *saveformat.Component
+	Node      *Node
+	Direction Direction
+	IsPipe    bool
+	Edge      *Edge // nil when not connected
+}
+
+// Node is an actor with factory or pipe connections, like a building, belt, splitter or pipeline.
+type Node struct {
+	Actor       *saveformat.Actor
+	Class       string
+	Family      model.Family
+	Tier        int     // belts and pipes only
+	Throughput  float64 // items or m³ per minute, belts and pipes only
+	Connections []*Connection
+}
+
+// IsTransport reports whether the node is a belt, lift or pipeline.
+func (node *Node) IsTransport() bool {
+	return node.Family == model.FamilyBelt || node.Family == model.FamilyPipe
+}
+
+// Edge links two connected components. It flows from From to To unless it is undirected,
+// which is only the case between two pipeline ends.
+type Edge struct {
+	From       *Connection
+	To         *Connection
+	Undirected bool
+	IsPipe     bool
+	Tier       int     // lowest belt or pipe tier at the ends, 0 for buildings snapped together
+	Throughput float64 // lowest belt or pipe throughput at the ends, 0 when unlimited
+}
+
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	nodes map[*saveformat.Actor]*Node
+}
+
+// NewGraph builds the logistics graph of all actors with factory or pipe connections.
+func NewGraph(save *saveformat.SaveIndex) (*Graph, error) {
+	graph := &Graph{nodes: make(map[*saveformat.Actor]*Node)}
+	connections := make(map[*saveformat.Component]*Connection)
+
+	for _, actor := range save.Actors {
+		node, err := newNode(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			continue
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		graph.nodes[actor] = node
+		for _, connection := range node.Connections {
+			connections[connection.Component] = connection
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		for _, connection := range node.Connections {
+			if connection.Edge != nil {
+				continue
+			}
+			props, err := connection.Component.DecodedProperties()
+			if err != nil {
+				return nil, err
+			}
+			ref, err := props.Object("mConnectedComponent")
+			if err != nil {
+				continue
+			}
+			other, ok := save.Component(ref.PathName)
+			if !ok || connections[other] == nil || connections[other].Edge != nil {
+				continue
+			}
+			graph.Edges = append(graph.Edges, connect(connection, connections[other]))
+		}
+	}
+	return graph, nil
+}
+
+func newNode(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Node, error) {
+	var node *Node
+	for _, component := range actor.Components {
+		isPipe := strings.HasPrefix(component.Header.TypePath, pipeConnectionPrefix)
+		if component.Header.TypePath != factoryConnectionType && !isPipe {
+			continue
+		}
+		if node == nil {
+			node = &Node{
+				Actor:  actor,
+				Class:  model.ClassName(actor.Header.TypePath),
+				Family: model.FamilyOf(actor.Header.TypePath),
+			}
+		}
+		node.Connections = append(node.Connections, &Connection{
+			Component: component,
+			Node:      node,
+			Direction: connectionDirection(component.Header.Name),
+			IsPipe:    isPipe,
+		})
+	}
+	if node == nil {
+		return nil, nil
+	}
+
+	switch node.Family {
+	case model.FamilyBelt:
+		belt, err := model.NewBelt(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		node.Tier, node.Throughput = belt.Tier, float64(belt.Speed)
+	case model.FamilyPipe:
+		pipe, err := model.NewPipe(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		node.Tier, node.Throughput = pipe.Tier, float64(pipe.FlowLimit)
+	}
+	return node, nil
+}
+
+// connectionDirection derives the direction from the connection name, e.g. "Input0",
+// "PipeOutputFactory" or "ConveyorAny1", which is the end of a belt.
+func connectionDirection(pathName string) Direction {
+	name := pathName[strings.LastIndexAny(pathName, ".:")+1:]
+	switch {
+	case strings.Contains(name, "Input"), name == "ConveyorAny0":
+		return DirectionInput
+	case strings.Contains(name, "Output"), name == "ConveyorAny1":
+		return DirectionOutput
+	}
+	return DirectionAny
+}
+
+func connect(a, b *Connection) *Edge {
+	edge := &Edge{From: a, To: b, IsPipe: a.IsPipe || b.IsPipe}
+	switch {
+	case a.Direction == DirectionInput || b.Direction == DirectionOutput:
+		edge.From, edge.To = b, a
+	case a.Direction == DirectionAny && b.Direction == DirectionAny:
+		edge.Undirected = true
+	}
+
+	for _, node := range []*Node{a.Node, b.Node} {
+		if !node.IsTransport() {
+			continue
+		}
+		if edge.Tier == 0 || node.Tier < edge.Tier {
+			edge.Tier = node.Tier
+		}
+		if edge.Throughput == 0 || node.Throughput < edge.Throughput {
+			edge.Throughput = node.Throughput
+		}
+	}
+	a.Edge, b.Edge = edge, edge
+	return edge
+}
+
+// Node returns the node of an actor, nil when the actor has no connections.
+func (graph *Graph) Node(actor *saveformat.Actor) *Node {
+	return graph.nodes[actor]
+}
+
+// Downstream returns every node the node delivers to, directly or through other nodes.
+func (graph *Graph) Downstream(node *Node) []*Node {
+	return walk(node, func(connection *Connection) *Connection {
+		if edge := connection.Edge; edge != nil && (edge.From == connection || edge.Undirected) {
+			return other(edge, connection)
+		}
+		return nil
+	})
+}
+
+// Upstream returns every node delivering to the node, directly or through other nodes.
+func (graph *Graph) Upstream(node *Node) []*Node {
+	return walk(node, func(connection *Connection) *Connection {
+		if edge := connection.Edge; edge != nil && (edge.To == connection || edge.Undirected) {
+			return other(edge, connection)
+		}
+		return nil
+	})
+}
+
+// walk returns the nodes reachable from start in breadth first order, without start itself.
+func walk(start *Node, next func(*Connection) *Connection) []*Node {
+	var nodes []*Node
+	visited := map[*Node]bool{start: true}
+	queue := []*Node{start}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, connection := range node.Connections {
+			target := next(connection)
+			if target == nil || visited[target.Node] {
+				continue
+			}
+			visited[target.Node] = true
+			nodes = append(nodes, target.Node)
+			queue = append(queue, target.Node)
+		}
+	}
+	return nodes
+}
+
+func other(edge *Edge, connection *Connection) *Connection {
+	if edge.From == connection {
+		return edge.To
+	}
+	return edge.From
+}
+
+// DisconnectedOutputs returns the output connections that are not connected to anything,
+// including the ends of belts.
+func (graph *Graph) DisconnectedOutputs() []*Connection {
+	var outputs []*Connection
+	for _, node := range graph.Nodes {
+		for _, connection := range node.Connections {
+			if connection.Direction == DirectionOutput && connection.Edge == nil {
+				outputs = append(outputs, connection)
+			}
+		}
+	}
+	return outputs
+}
+
+// outputs returns the connected and disconnected outputs of the node, sorted by name.
+func (node *Node) outputs(isPipe bool) []*Connection {
+	var outputs []*Connection
+	for _, connection := range node.Connections {
+		if connection.Direction == DirectionOutput && connection.IsPipe == isPipe {
+			outputs = append(outputs, connection)
+		}
+	}
+	slices.SortFunc(outputs, func(a, b *Connection) int {
+		return strings.Compare(a.Component.Header.Name, b.Component.Header.Name)
+	})
+	return outputs
+}
@@ -0,0 +1,115 @@
+package logistics_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/logistics"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestGraphTestSave(t *testing.T) {
+	graph, err := logistics.NewGraph(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the buildings in the test save are not connected
+	if len(graph.Nodes) < 15 || len(graph.Edges) != 0 {
+		t.Error("Nodes:", len(graph.Nodes), "edges:", len(graph.Edges))
+	}
+	outputs := 0
+	for _, output := range graph.DisconnectedOutputs() {
+		if output.Direction != logistics.DirectionOutput || output.Edge != nil {
+			t.Error("Invalid disconnected output:", output.Component.Header.Name)
+		}
+		if output.Node.Class == "Build_ConstructorMk1_C" {
+			outputs++
+		}
+	}
+	if outputs == 0 {
+		t.Error("Constructor outputs are not disconnected")
+	}
+}
+
+// testLine builds a constructor making screws at 200%, connected by a Mk1 belt to a second constructor.
+func testLine() *saveformat.SaveIndex {
+	var level testsave.Level
+	addConnection := func(name string, parent string, connectedTo string) {
+		var props []saveformat.Property
+		if connectedTo != "" {
+			props = append(props, saveformat.Property{Name: "mConnectedComponent", Type: "ObjectProperty", Value: testsave.Ref(connectedTo)})
+		}
+		level.AddComponent(name, "/Script/FactoryGame.FGFactoryConnectionComponent", parent, props...)
+	}
+
+	level.AddActor("Screws", "Build_ConstructorMk1.Build_ConstructorMk1_C",
+		saveformat.Property{Name: "mCurrentRecipe", Type: "ObjectProperty", Value: testsave.Ref("/Game/Recipe_Screw.Recipe_Screw_C")},
+		saveformat.Property{Name: "mCurrentPotential", Type: "FloatProperty", Value: float32(2)},
+		saveformat.Property{Name: "mPendingPotential", Type: "FloatProperty", Value: float32(2)},
+	)
+	level.AddActor("Belt", "Build_ConveyorBeltMk1.Build_ConveyorBeltMk1_C")
+	level.AddActor("Consumer", "Build_ConstructorMk1.Build_ConstructorMk1_C")
+
+	addConnection("Screws.Input0", "Screws", "")
+	addConnection("Screws.Output0", "Screws", "Belt.ConveyorAny0")
+	addConnection("Belt.ConveyorAny0", "Belt", "Screws.Output0")
+	addConnection("Belt.ConveyorAny1", "Belt", "Consumer.Input0")
+	addConnection("Consumer.Input0", "Consumer", "Belt.ConveyorAny1")
+	addConnection("Consumer.Output0", "Consumer", "")
+
+	return level.Index()
+}
+
+func TestGraph(t *testing.T) {
+	save := testLine()
+	graph, err := logistics.NewGraph(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Fatal("Nodes:", len(graph.Nodes), "edges:", len(graph.Edges))
+	}
+
+	screws, belt, consumer := graph.Nodes[0], graph.Nodes[1], graph.Nodes[2]
+	if belt.Tier != 1 || belt.Throughput != 60 {
+		t.Error("Belt:", belt.Tier, belt.Throughput)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Undirected || edge.Tier != 1 || edge.Throughput != 60 {
+			t.Error("Invalid edge:", edge.From.Component.Header.Name, edge.To.Component.Header.Name, edge.Tier, edge.Throughput)
+		}
+	}
+	if edge := graph.Edges[0]; edge.From.Node != screws || edge.To.Node != belt {
+		t.Error("Edge direction:", edge.From.Component.Header.Name, "->", edge.To.Component.Header.Name)
+	}
+
+	if downstream := graph.Downstream(screws); !slices.Equal(downstream, []*logistics.Node{belt, consumer}) {
+		t.Error("Downstream:", len(downstream))
+	}
+	if upstream := graph.Upstream(consumer); !slices.Equal(upstream, []*logistics.Node{belt, screws}) {
+		t.Error("Upstream:", len(upstream))
+	}
+	if upstream := graph.Upstream(screws); len(upstream) != 0 {
+		t.Error("Upstream of producer:", len(upstream))
+	}
+
+	outputs := graph.DisconnectedOutputs()
+	if len(outputs) != 1 || outputs[0].Component.Header.Name != "Consumer.Output0" {
+		t.Error("Disconnected outputs:", len(outputs))
+	}
+
+	producer, err := model.NewProductionBuilding(save, screws.Actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bottlenecks := graph.Bottlenecks([]*model.ProductionBuilding{producer})
+	if len(bottlenecks) != 1 {
+		t.Fatal("Bottlenecks:", len(bottlenecks))
+	}
+	if b := bottlenecks[0]; b.Limit != belt || b.Rate != 80 || b.Throughput != 60 || b.Item != "Desc_IronScrew_C" {
+		t.Error("Invalid bottleneck:", b.Item, b.Rate, b.Throughput)
+	}
+}
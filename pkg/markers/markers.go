@@ -0,0 +1,179 @@
+// Package markers reads the map markers and stamps placed by players and exports them as GeoJSON.
+package markers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const mapManagerClass = "FGMapManager"
+
+// Color is a linear color as saved.
+type Color struct {
+	R, G, B, A float32
+}
+
+// Hex returns the sRGB color as #rrggbb.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", srgb(c.R), srgb(c.G), srgb(c.B))
+}
+
+func srgb(linear float32) uint8 {
+	v := math.Max(0, math.Min(1, float64(linear)))
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(v * 255))
+}
+
+type MapMarker struct {
+	ID       int
+	Name     string
+	Category string
+	Type     string // representation type without its prefix, e.g. MapMarker or Stamp
+	IconID   int
+	Color    Color
+	Scale    float32
+	X, Y, Z  float32
+	Compass  string // compass view distance without its prefix, e.g. Always, empty when not saved
+	PlacedBy string // account id of the player who placed the marker
+}
+
+func (marker *MapMarker) IsStamp() bool {
+	return marker.Type == "Stamp"
+}
+
+// IsOnCompass returns whether the marker is shown on the compass at some distance.
+func (marker *MapMarker) IsOnCompass() bool {
+	return marker.Compass != "" && marker.Compass != "Off"
+}
+
+// Markers returns the map markers and stamps of the map manager in save order.
+func Markers(save *saveformat.SaveIndex) ([]*MapMarker, error) {
+	var markers []*MapMarker
+	for _, actor := range save.Actors {
+		if model.ClassName(actor.Header.TypePath) != mapManagerClass {
+			continue
+		}
+		props, err := actor.DecodedProperties()
+		if err != nil {
+			return nil, err
+		}
+		elements, _ := props.Array("mMapMarkers")
+		for _, element := range elements {
+			if fields, ok := element.([]saveformat.Property); ok {
+				markers = append(markers, newMapMarker(fields))
+			}
+		}
+	}
+	return markers, nil
+}
+
+func newMapMarker(props saveformat.Properties) *MapMarker {
+	marker := &MapMarker{Scale: 1}
+	id, _ := props.Int("MarkerID")
+	icon, _ := props.Int("IconID")
+	marker.ID, marker.IconID = int(id), int(icon)
+	marker.Name, _ = props.Str("Name")
+	marker.Category, _ = props.Str("CategoryName")
+	marker.PlacedBy, _ = props.Str("MarkerPlacedByAccountID")
+	if scale, err := props.Float("Scale"); err == nil {
+		marker.Scale = float32(scale)
+	}
+
+	markerType, _ := props.Str("MapMarkerType")
+	compass, _ := props.Str("CompassViewDistance")
+	marker.Type, marker.Compass = enumValue(markerType), enumValue(compass)
+
+	if location, err := props.Get("Location"); err == nil {
+		if v, ok := location.Value.(readfields.Vector); ok {
+			marker.X, marker.Y, marker.Z = float32(v.X), float32(v.Y), float32(v.Z)
+		}
+	}
+	if color, err := props.Get("Color"); err == nil {
+		if c, ok := color.Value.(readfields.LinearColor); ok {
+			marker.Color = Color(c)
+		}
+	}
+	return marker
+}
+
+// enumValue strips the enum and value prefixes, e.g. ERepresentationType::RT_Stamp becomes Stamp.
+func enumValue(value string) string {
+	if i := strings.LastIndex(value, "::"); i >= 0 {
+		value = value[i+2:]
+	}
+	if _, name, ok := strings.Cut(value, "_"); ok {
+		return name
+	}
+	return value
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	Geometry   point             `json:"geometry"`
+	Properties featureProperties `json:"properties"`
+}
+
+type point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type featureProperties struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Category string  `json:"category,omitempty"`
+	Type     string  `json:"type"`
+	IconID   int     `json:"iconId"`
+	Color    string  `json:"color"`
+	Scale    float32 `json:"scale"`
+	Compass  string  `json:"compass,omitempty"`
+	Z        float32 `json:"z"`
+}
+
+// WriteGeoJSON writes the markers as a GeoJSON feature collection of points in the pixel space of
+// the map projection, for flat map viewers such as Leaflet's CRS.Simple. The GeoJSON coordinates
+// are not longitude and latitude: x is the pixel column and y counts up from the bottom (south)
+// edge of the map image, so north is +y as GeoJSON expects, while world Y points south. The world
+// height is kept in the z property, in centimeters.
+func WriteGeoJSON(w io.Writer, markers []*MapMarker, projection geometry.MapProjection) error {
+	collection := featureCollection{Type: "FeatureCollection", Features: make([]feature, 0, len(markers))}
+	for _, marker := range markers {
+		x, y := projection.Pixel(geometry.Vector{X: float64(marker.X), Y: float64(marker.Y)})
+		collection.Features = append(collection.Features, feature{
+			Type:     "Feature",
+			Geometry: point{Type: "Point", Coordinates: [2]float64{x, projection.Height - y}},
+			Properties: featureProperties{
+				ID:       marker.ID,
+				Name:     marker.Name,
+				Category: marker.Category,
+				Type:     marker.Type,
+				IconID:   marker.IconID,
+				Color:    marker.Color.Hex(),
+				Scale:    marker.Scale,
+				Compass:  marker.Compass,
+				Z:        marker.Z,
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
@@ -0,0 +1,93 @@
+package markers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/markers"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestMarkersTestSave(t *testing.T) {
+	// the test save has a map manager without markers
+	mapMarkers, err := markers.Markers(testsave.Load(t))
+	if err != nil || len(mapMarkers) != 0 {
+		t.Error("Markers:", len(mapMarkers), err)
+	}
+}
+
+func marker(id byte, name string, markerType string, compass string) []saveformat.Property {
+	return []saveformat.Property{
+		{Name: "MarkerID", Type: "ByteProperty", Value: id},
+		{Name: "Location", Type: "StructProperty", InnerType: "Vector_NetQuantize", Value: readfields.Vector{X: 100, Y: -200, Z: 300}},
+		{Name: "Name", Type: "StrProperty", Value: name},
+		{Name: "MapMarkerType", Type: "EnumProperty", Value: "ERepresentationType::" + markerType},
+		{Name: "IconID", Type: "IntProperty", Value: int32(652)},
+		{Name: "Color", Type: "StructProperty", InnerType: "LinearColor", Value: readfields.LinearColor{R: 1, G: 0.2140, B: 0, A: 1}},
+		{Name: "CompassViewDistance", Type: "EnumProperty", Value: "ECompassViewDistance::" + compass},
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	var level testsave.Level
+	level.AddActor("Persistent_Level:PersistentLevel.MapManager", "FGMapManager",
+		saveformat.Property{Name: "mMapMarkers", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{Value: []any{
+			marker(0, "Home", "RT_MapMarker", "CVD_Always"),
+			marker(1, "", "RT_Stamp", "CVD_Off"),
+		}}},
+	).TypePath = "/Script/FactoryGame.FGMapManager"
+	mapMarkers, err := markers.Markers(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapMarkers) != 2 {
+		t.Fatal("Markers:", len(mapMarkers))
+	}
+
+	home, stamp := mapMarkers[0], mapMarkers[1]
+	if home.Name != "Home" || home.IsStamp() || !home.IsOnCompass() || home.IconID != 652 || home.X != 100 || home.Y != -200 {
+		t.Error("Invalid marker:", *home)
+	}
+	if stamp.ID != 1 || !stamp.IsStamp() || stamp.IsOnCompass() || stamp.Scale != 1 {
+		t.Error("Invalid stamp:", *stamp)
+	}
+	if hex := home.Color.Hex(); hex != "#ff7f00" {
+		t.Error("Color:", hex)
+	}
+
+	var buf bytes.Buffer
+	projection := geometry.NewMapProjection(1000)
+	if err := markers.WriteGeoJSON(&buf, mapMarkers, projection); err != nil {
+		t.Fatal(err)
+	}
+	var geoJSON struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates []float64
+			}
+			Properties map[string]any
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &geoJSON); err != nil {
+		t.Fatal(err)
+	}
+	if geoJSON.Type != "FeatureCollection" || len(geoJSON.Features) != 2 {
+		t.Fatal("Invalid GeoJSON:", buf.String())
+	}
+	feature := geoJSON.Features[0]
+	if feature.Geometry.Type != "Point" || len(feature.Geometry.Coordinates) != 2 || feature.Properties["name"] != "Home" || feature.Properties["color"] != "#ff7f00" || feature.Properties["z"] != 300.0 {
+		t.Fatal("Invalid feature:", feature)
+	}
+	// the marker is just north of the map center, y counts up from the south edge
+	x, y := projection.Pixel(geometry.Vector{X: 100, Y: -200})
+	if c := feature.Geometry.Coordinates; math.Abs(c[0]-x) > 1e-9 || math.Abs(c[1]-(1000-y)) > 1e-9 || c[1] <= 500 {
+		t.Error("Coordinates:", c, "want", x, 1000-y)
+	}
+}
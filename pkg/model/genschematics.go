@@ -0,0 +1,93 @@
+//go:build ignore
+
+// Genschematics writes schematics.json from the Docs.json shipped with the game, in
+// CommunityResources/Docs/. It keeps the class and display name of every schematic.
+//
+//	go run genschematics.go -docs "$SATISFACTORY_DOCS" -out schematics.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+type schematic struct {
+	Class string `json:"class"`
+	Name  string `json:"name"`
+}
+
+func main() {
+	docsPath := flag.String("docs", "", "path of Docs.json")
+	outPath := flag.String("out", "schematics.json", "output file")
+	flag.Parse()
+	if *docsPath == "" {
+		log.Fatal("-docs is required")
+	}
+
+	data, err := os.ReadFile(*docsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var docs []struct {
+		NativeClass string
+		Classes     []struct {
+			ClassName   string
+			DisplayName string `json:"mDisplayName"`
+		}
+	}
+	if err := json.Unmarshal(decodeUTF16(data), &docs); err != nil {
+		log.Fatal("parsing ", *docsPath, ": ", err)
+	}
+
+	var schematics []schematic
+	for _, native := range docs {
+		if !strings.HasSuffix(native.NativeClass, "FGSchematic'") {
+			continue
+		}
+		for _, class := range native.Classes {
+			if class.DisplayName != "" {
+				schematics = append(schematics, schematic{Class: class.ClassName, Name: class.DisplayName})
+			}
+		}
+	}
+	slices.SortFunc(schematics, func(a, b schematic) int { return strings.Compare(a.Class, b.Class) })
+
+	var out bytes.Buffer
+	out.WriteString("{\n\t\"schematics\": [\n")
+	for i, s := range schematics {
+		line, err := json.Marshal(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out.WriteString("\t\t")
+		out.Write(line)
+		if i < len(schematics)-1 {
+			out.WriteByte(',')
+		}
+		out.WriteByte('\n')
+	}
+	out.WriteString("\t]\n}\n")
+	if err := os.WriteFile(*outPath, out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(schematics), "schematics")
+}
+
+// decodeUTF16 converts the UTF-16 Docs.json of the game to UTF-8, other input is returned as is.
+func decodeUTF16(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xfe {
+		return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+	}
+	return []byte(string(utf16.Decode(units)))
+}
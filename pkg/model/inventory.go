@@ -0,0 +1,103 @@
+package model
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// inventoryType is the type path of inventory components, subclasses share it as prefix.
+const inventoryType = "/Script/FactoryGame.FGInventoryComponent"
+
+// ItemStack is a slot of an inventory, empty slots are left out.
+type ItemStack struct {
+	Slot      int
+	ItemClass string // path of the item descriptor class
+	Count     int
+}
+
+// Inventory is a decoded FGInventoryComponent.
+type Inventory struct {
+	Component *saveformat.Component
+	Stacks    []ItemStack
+}
+
+func NewInventory(component *saveformat.Component) (*Inventory, error) {
+	props, err := component.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{Component: component}
+	stacks, _ := props.Array("mInventoryStacks")
+	for slot, value := range stacks {
+		stack, _ := value.([]saveformat.Property)
+		item, _ := saveformat.Properties(stack).Get("Item")
+		inventoryItem, _ := item.Value.(saveformat.InventoryItem)
+		itemClass := inventoryItem.Reference.PathName
+		count := intProperty(stack, "NumItems")
+		if itemClass == "" || count == 0 {
+			continue
+		}
+		inventory.Stacks = append(inventory.Stacks, ItemStack{Slot: slot, ItemClass: itemClass, Count: count})
+	}
+	return inventory, nil
+}
+
+// InventoryProperty resolves an object property that references an inventory component, nil when
+// it is not saved.
+func InventoryProperty(save *saveformat.SaveIndex, props saveformat.Properties, name string) (*Inventory, error) {
+	component := ComponentProperty(save, props, name)
+	if component == nil {
+		return nil, nil
+	}
+	return NewInventory(component)
+}
+
+// ItemTotal is the number of items of one class held by an actor over all its inventories.
+type ItemTotal struct {
+	Owner     *saveformat.Actor
+	ItemClass string
+	Count     int
+}
+
+// Inventories returns the item totals of every actor with an inventory, such as storage,
+// production buffers, players and vehicles. Totals are in save order, then by first slot.
+func Inventories(save *saveformat.SaveIndex) ([]ItemTotal, error) {
+	var totals []ItemTotal
+	ownerTotals := make(map[*saveformat.Actor]map[string]int)
+	for _, component := range save.Components {
+		owner := component.Owner()
+		if owner == nil || !strings.HasPrefix(component.Header.TypePath, inventoryType) {
+			continue
+		}
+
+		inventory, err := NewInventory(component)
+		if err != nil {
+			return nil, err
+		}
+		if ownerTotals[owner] == nil {
+			ownerTotals[owner] = make(map[string]int)
+		}
+		for _, stack := range inventory.Stacks {
+			i, ok := ownerTotals[owner][stack.ItemClass]
+			if !ok {
+				i = len(totals)
+				ownerTotals[owner][stack.ItemClass] = i
+				totals = append(totals, ItemTotal{Owner: owner, ItemClass: stack.ItemClass})
+			}
+			totals[i].Count += stack.Count
+		}
+	}
+
+	// components are in save order, group the totals of each owner
+	owners := make(map[*saveformat.Actor]int)
+	for _, actor := range save.Actors {
+		owners[actor] = len(owners)
+	}
+	slices.SortStableFunc(totals, func(a, b ItemTotal) int {
+		return owners[a.Owner] - owners[b.Owner]
+	})
+	return totals, nil
+}
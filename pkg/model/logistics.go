@@ -0,0 +1,152 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const (
+	factoryConnectionType  = "/Script/FactoryGame.FGFactoryConnectionComponent"
+	pipeConnectionType     = "/Script/FactoryGame.FGPipeConnectionComponent"
+	trainStationIdentifier = "/Script/FactoryGame.FGTrainStationIdentifier"
+)
+
+// beltSpeeds are the items per minute of conveyor belts and lifts by tier.
+var beltSpeeds = [...]float32{60, 120, 270, 480, 780, 1200}
+
+// pipeFlows are the m³ per minute of pipelines by tier.
+var pipeFlows = [...]float32{300, 600}
+
+// Storage is a storage container, fluid buffer or the player's storage in the HUB.
+type Storage struct {
+	Building
+	Inventory *Inventory // nil for fluid buffers, which keep their fluid in the trailing bytes
+}
+
+func NewStorage(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Storage, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := &Storage{Building: building}
+	if storage.Inventory, err = InventoryProperty(save, building.Properties, "mStorageInventory"); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// Belt is a conveyor belt or conveyor lift.
+type Belt struct {
+	Building
+	Tier        int
+	IsLift      bool
+	Speed       float32 // items per minute
+	Connections []*saveformat.Component
+}
+
+func NewBelt(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Belt, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	belt := &Belt{
+		Building:    building,
+		Tier:        tier(building.Class),
+		IsLift:      strings.HasPrefix(building.Class, "Build_ConveyorLift"),
+		Connections: componentsOfType(actor, factoryConnectionType),
+	}
+	belt.Speed = beltSpeeds[min(belt.Tier, len(beltSpeeds))-1]
+	return belt, nil
+}
+
+// Pipe is a pipeline segment.
+type Pipe struct {
+	Building
+	Tier        int
+	FlowLimit   float32 // m³ per minute
+	Connections []*saveformat.Component
+}
+
+func NewPipe(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Pipe, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := &Pipe{
+		Building:    building,
+		Tier:        tier(building.Class),
+		Connections: componentsOfType(actor, pipeConnectionType),
+	}
+	pipe.FlowLimit = pipeFlows[min(pipe.Tier, len(pipeFlows))-1]
+	return pipe, nil
+}
+
+// TrainStation is a train station or a freight platform.
+type TrainStation struct {
+	Building
+	IsPlatform   bool
+	IsInLoadMode bool       // platforms only
+	Inventory    *Inventory // freight platforms only
+	// Identifier is the FGTrainStationIdentifier actor holding the station name, stations only.
+	Identifier *saveformat.Actor
+	Name       string
+}
+
+func NewTrainStation(save *saveformat.SaveIndex, actor *saveformat.Actor) (*TrainStation, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	station := &TrainStation{
+		Building:     building,
+		IsPlatform:   building.Class != "Build_TrainStation_C",
+		IsInLoadMode: boolProperty(building.Properties, "mIsInLoadMode"),
+	}
+	if station.Inventory, err = InventoryProperty(save, building.Properties, "mInventory"); err != nil {
+		return nil, err
+	}
+	for _, reference := range save.ReferencedBy(actor.Header.Name) {
+		if identifier := reference.From.Owner(); identifier != nil && identifier.Header.TypePath == trainStationIdentifier {
+			station.Identifier = identifier
+		}
+	}
+	if station.Identifier != nil {
+		props, err := station.Identifier.DecodedProperties()
+		if err != nil {
+			return nil, err
+		}
+		if station.Name, err = textProperty(props, "mStationName"); err != nil {
+			return nil, err
+		}
+	}
+	return station, nil
+}
+
+// VehicleStation is a truck station, where vehicles driving a recorded path load, unload and refuel.
+type VehicleStation struct {
+	Building
+	IsInLoadMode  bool
+	Inventory     *Inventory
+	FuelInventory *Inventory
+}
+
+func NewVehicleStation(save *saveformat.SaveIndex, actor *saveformat.Actor) (*VehicleStation, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	station := &VehicleStation{Building: building, IsInLoadMode: boolProperty(building.Properties, "mIsInLoadMode")}
+	if station.Inventory, err = InventoryProperty(save, building.Properties, "mInventory"); err != nil {
+		return nil, err
+	}
+	if station.FuelInventory, err = InventoryProperty(save, building.Properties, "mFuelInventory"); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
@@ -0,0 +1,206 @@
+// Package model turns the raw actors of a save into typed buildings.
+package model
+
+import (
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// ClassName returns the class of a type path, e.g. "Build_ConstructorMk1_C".
+func ClassName(typePath string) string {
+	return typePath[strings.LastIndexByte(typePath, '.')+1:]
+}
+
+type Family int
+
+const (
+	FamilyUnknown Family = iota
+	FamilyProduction
+	FamilyGenerator
+	FamilyExtractor
+	FamilyStorage
+	FamilyBelt
+	FamilyPipe
+	FamilyTrainStation
+	FamilyPowerPole
+	FamilyVehicleStation
+)
+
+var families = map[string]Family{
+	"Build_SmelterMk1_C":      FamilyProduction,
+	"Build_FoundryMk1_C":      FamilyProduction,
+	"Build_ConstructorMk1_C":  FamilyProduction,
+	"Build_AssemblerMk1_C":    FamilyProduction,
+	"Build_ManufacturerMk1_C": FamilyProduction,
+	"Build_OilRefinery_C":     FamilyProduction,
+	"Build_Packager_C":        FamilyProduction,
+	"Build_Blender_C":         FamilyProduction,
+	"Build_HadronCollider_C":  FamilyProduction,
+	"Build_Converter_C":       FamilyProduction,
+	"Build_QuantumEncoder_C":  FamilyProduction,
+
+	"Build_GeneratorIntegratedBiomass_C": FamilyGenerator,
+	"Build_GeneratorBiomass_Automated_C": FamilyGenerator,
+	"Build_GeneratorCoal_C":              FamilyGenerator,
+	"Build_GeneratorFuel_C":              FamilyGenerator,
+	"Build_GeneratorNuclear_C":           FamilyGenerator,
+	"Build_GeneratorGeoThermal_C":        FamilyGenerator,
+	"Build_AlienPowerBuilding_C":         FamilyGenerator,
+
+	"Build_MinerMk1_C":          FamilyExtractor,
+	"Build_MinerMk2_C":          FamilyExtractor,
+	"Build_MinerMk3_C":          FamilyExtractor,
+	"Build_OilPump_C":           FamilyExtractor,
+	"Build_WaterPump_C":         FamilyExtractor,
+	"Build_FrackingExtractor_C": FamilyExtractor,
+	"Build_FrackingSmasher_C":   FamilyExtractor,
+
+	"Build_StorageContainerMk1_C": FamilyStorage,
+	"Build_StorageContainerMk2_C": FamilyStorage,
+	"Build_StorageIntegrated_C":   FamilyStorage,
+	"Build_StoragePlayer_C":       FamilyStorage,
+	"Build_PipeStorageTank_C":     FamilyStorage,
+	"Build_IndustrialTank_C":      FamilyStorage,
+
+	"Build_ConveyorBeltMk1_C": FamilyBelt,
+	"Build_ConveyorBeltMk2_C": FamilyBelt,
+	"Build_ConveyorBeltMk3_C": FamilyBelt,
+	"Build_ConveyorBeltMk4_C": FamilyBelt,
+	"Build_ConveyorBeltMk5_C": FamilyBelt,
+	"Build_ConveyorBeltMk6_C": FamilyBelt,
+	"Build_ConveyorLiftMk1_C": FamilyBelt,
+	"Build_ConveyorLiftMk2_C": FamilyBelt,
+	"Build_ConveyorLiftMk3_C": FamilyBelt,
+	"Build_ConveyorLiftMk4_C": FamilyBelt,
+	"Build_ConveyorLiftMk5_C": FamilyBelt,
+	"Build_ConveyorLiftMk6_C": FamilyBelt,
+
+	"Build_Pipeline_C":                FamilyPipe,
+	"Build_Pipeline_NoIndicator_C":    FamilyPipe,
+	"Build_PipelineMK2_C":             FamilyPipe,
+	"Build_PipelineMK2_NoIndicator_C": FamilyPipe,
+
+	"Build_TrainStation_C":              FamilyTrainStation,
+	"Build_TrainDockingStation_C":       FamilyTrainStation,
+	"Build_TrainDockingStationLiquid_C": FamilyTrainStation,
+	"Build_TrainPlatformEmpty_C":        FamilyTrainStation,
+	"Build_TrainPlatformEmpty_02_C":     FamilyTrainStation,
+
+	"Build_PowerPoleMk1_C":            FamilyPowerPole,
+	"Build_PowerPoleMk2_C":            FamilyPowerPole,
+	"Build_PowerPoleMk3_C":            FamilyPowerPole,
+	"Build_PowerPoleWall_C":           FamilyPowerPole,
+	"Build_PowerPoleWall_Mk2_C":       FamilyPowerPole,
+	"Build_PowerPoleWall_Mk3_C":       FamilyPowerPole,
+	"Build_PowerPoleWallDouble_C":     FamilyPowerPole,
+	"Build_PowerPoleWallDouble_Mk2_C": FamilyPowerPole,
+	"Build_PowerPoleWallDouble_Mk3_C": FamilyPowerPole,
+	"Build_PowerTower_C":              FamilyPowerPole,
+	"Build_PowerTowerPlatform_C":      FamilyPowerPole,
+
+	"Build_TruckStation_C": FamilyVehicleStation,
+}
+
+// FamilyOf returns the building family of a type path.
+func FamilyOf(typePath string) Family {
+	return families[ClassName(typePath)]
+}
+
+// Building holds what all typed buildings share, unknown fields are still in Properties.
+type Building struct {
+	Actor           *saveformat.Actor
+	Class           string
+	Properties      saveformat.Properties
+	BuiltWithRecipe string
+	PowerInfo       *PowerInfo // nil for buildings without power
+}
+
+// NewBuilding reads the fields shared by all buildings, also for buildings of unknown families.
+func NewBuilding(save *saveformat.SaveIndex, actor *saveformat.Actor) (Building, error) {
+	props, err := actor.DecodedProperties()
+	if err != nil {
+		return Building{}, err
+	}
+
+	building := Building{
+		Actor:           actor,
+		Class:           ClassName(actor.Header.TypePath),
+		Properties:      props,
+		BuiltWithRecipe: objectProperty(props, "mBuiltWithRecipe").PathName,
+	}
+	if component := ComponentProperty(save, props, "mPowerInfo"); component != nil {
+		building.PowerInfo, err = newPowerInfo(component)
+	}
+	return building, err
+}
+
+// Buildings holds the typed buildings of a save, grouped by family.
+type Buildings struct {
+	Production      []*ProductionBuilding
+	Generators      []*Generator
+	Extractors      []*Extractor
+	Storage         []*Storage
+	Belts           []*Belt
+	Pipes           []*Pipe
+	TrainStations   []*TrainStation
+	PowerPoles      []*PowerPole
+	VehicleStations []*VehicleStation
+}
+
+// NewBuildings builds the typed buildings of all known families in the save.
+func NewBuildings(save *saveformat.SaveIndex) (*Buildings, error) {
+	var buildings Buildings
+	for _, actor := range save.Actors {
+		var err error
+		switch FamilyOf(actor.Header.TypePath) {
+		case FamilyProduction:
+			err = appendBuilding(&buildings.Production, save, actor, NewProductionBuilding)
+		case FamilyGenerator:
+			err = appendBuilding(&buildings.Generators, save, actor, NewGenerator)
+		case FamilyExtractor:
+			err = appendBuilding(&buildings.Extractors, save, actor, NewExtractor)
+		case FamilyStorage:
+			err = appendBuilding(&buildings.Storage, save, actor, NewStorage)
+		case FamilyBelt:
+			err = appendBuilding(&buildings.Belts, save, actor, NewBelt)
+		case FamilyPipe:
+			err = appendBuilding(&buildings.Pipes, save, actor, NewPipe)
+		case FamilyTrainStation:
+			err = appendBuilding(&buildings.TrainStations, save, actor, NewTrainStation)
+		case FamilyPowerPole:
+			err = appendBuilding(&buildings.PowerPoles, save, actor, NewPowerPole)
+		case FamilyVehicleStation:
+			err = appendBuilding(&buildings.VehicleStations, save, actor, NewVehicleStation)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &buildings, nil
+}
+
+func appendBuilding[T any](list *[]*T, save *saveformat.SaveIndex, actor *saveformat.Actor, build func(*saveformat.SaveIndex, *saveformat.Actor) (*T, error)) error {
+	building, err := build(save, actor)
+	if err != nil {
+		return err
+	}
+	*list = append(*list, building)
+	return nil
+}
+
+// tier returns the number after "Mk" in a class name, or 1 when there is none.
+func tier(class string) int {
+	i := strings.LastIndex(strings.ToLower(class), "mk")
+	if i < 0 {
+		return 1
+	}
+	n := 0
+	for _, c := range class[i+2:] {
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return max(n, 1)
+}
@@ -0,0 +1,189 @@
+package model_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestBuildings(t *testing.T) {
+	buildings, err := model.NewBuildings(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(buildings.Production) != 15 {
+		t.Error("Production buildings:", len(buildings.Production), "want 15")
+	}
+	recipes := 0
+	for _, building := range buildings.Production {
+		if building.Recipe != "" {
+			recipes++
+		}
+		if building.PowerInfo == nil || building.InputInventory == nil || building.OutputInventory == nil {
+			t.Error("Production building without power info or inventories:", building.Actor.Header.Name)
+		}
+		if building.Potential <= 0 || building.ProductionBoost < 1 {
+			t.Error("Invalid clock speed:", building.Actor.Header.Name, building.Potential, building.ProductionBoost)
+		}
+	}
+	if recipes != 7 {
+		t.Error("Buildings with a recipe:", recipes, "want 7")
+	}
+
+	if len(buildings.Extractors) != 2 {
+		t.Fatal("Extractors:", len(buildings.Extractors), "want 2")
+	}
+	for _, extractor := range buildings.Extractors {
+		if extractor.Resource.PathName == "" || extractor.Tier < 2 {
+			t.Error("Extractor without resource node or tier:", extractor.Actor.Header.Name)
+		}
+	}
+
+	if len(buildings.Generators) != 2 || len(buildings.Storage) != 1 || len(buildings.PowerPoles) != 7 {
+		t.Error("Generators, storage, power poles:", len(buildings.Generators), len(buildings.Storage), len(buildings.PowerPoles))
+	}
+	for _, pole := range buildings.PowerPoles {
+		if pole.MaxConnections != 4 || len(pole.Connections) != 1 {
+			t.Error("Power pole connections:", pole.Actor.Header.Name, pole.MaxConnections, len(pole.Connections))
+		}
+	}
+}
+
+func TestInventories(t *testing.T) {
+	save := testsave.Load(t)
+	totals, err := model.Inventories(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	seen := make(map[*saveformat.Actor]bool)
+	for i, total := range totals {
+		if seen[total.Owner] && totals[i-1].Owner != total.Owner {
+			t.Error("Totals of owner are not grouped:", total.Owner.Header.Name)
+		}
+		seen[total.Owner] = true
+		counts[model.ClassName(total.Owner.Header.TypePath)+" "+model.ClassName(total.ItemClass)] += total.Count
+	}
+
+	want := map[string]int{
+		"Char_Player_C Desc_IronScrew_C":     3200,
+		"Char_Player_C Desc_ModularFrame_C":  60,
+		"Build_MinerMk2_C Desc_OreIron_C":    100,
+		"Build_QuantumEncoder_C Desc_WAT1_C": 2,
+	}
+	for key, count := range want {
+		if counts[key] != count {
+			t.Error("Items of", key, counts[key], "want", count)
+		}
+	}
+}
+
+func TestProgression(t *testing.T) {
+	progress, err := model.Progression(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(progress.Schematics) != 277 || len(progress.Milestones) != 50 || len(progress.Research) != 106 || len(progress.Alternates) != 112 {
+		t.Error("Schematics:", len(progress.Schematics), "milestones:", len(progress.Milestones), "research:", len(progress.Research), "alternates:", len(progress.Alternates))
+	}
+	if len(progress.Tiers) != 10 || progress.Tiers[0] != 0 || progress.Tiers[9] != 9 {
+		t.Error("Tiers:", progress.Tiers)
+	}
+	if progress.Phase != 7 || !progress.IsGameCompleted || len(progress.ResearchTrees) == 0 {
+		t.Error("Invalid phase or research trees:", progress.GamePhase, progress.IsGameCompleted, progress.ResearchTrees)
+	}
+
+	classes := make(map[string]model.Schematic)
+	for _, schematic := range progress.Schematics {
+		classes[model.ClassName(schematic.Path)] = schematic
+		if schematic.Name != model.SchematicName(schematic.Path) {
+			t.Error("Name of", schematic.Path, "is", schematic.Name)
+		}
+	}
+	for class, want := range map[string]model.Schematic{
+		"Schematic_5-4-1_C":                     {Kind: model.SchematicMilestone, Tier: 5},
+		"Schematic_Alternate_OCSupercomputer_C": {Kind: model.SchematicAlternate},
+		"Research_Caterium_4_1_1_C":             {Kind: model.SchematicResearch, Tree: "Caterium"},
+		"Research_AO_Pre_Rebar_C":               {Kind: model.SchematicResearch, Tree: "Alien Organisms"},
+	} {
+		if schematic, ok := classes[class]; !ok || schematic.Kind != want.Kind || schematic.Tier != want.Tier || schematic.Tree != want.Tree {
+			t.Error("Schematic", class, "is", schematic)
+		}
+	}
+	if !slices.Contains(progress.ResearchTrees, "Alien Organisms") {
+		t.Error("Research trees:", progress.ResearchTrees)
+	}
+	if progress.ActiveMilestone != nil {
+		t.Error("Active milestone of a completed game:", progress.ActiveMilestone.Path)
+	}
+}
+
+func TestSchematicName(t *testing.T) {
+	// classes that are not in the embedded table keep their class name
+	if name := model.SchematicName("/Game/FactoryGame/Schematics/Modded/Schematic_Modded.Schematic_Modded_C"); name != "Schematic_Modded_C" {
+		t.Error("Name of an unknown schematic:", name)
+	}
+}
+
+func TestActiveMilestone(t *testing.T) {
+	const path = "/Game/FactoryGame/Schematics/Progression/Schematic_3-2.Schematic_3-2_C"
+	var level testsave.Level
+	level.AddActor("Persistent_Level:PersistentLevel.SchematicManager", "FactoryGame/Schematics/Progression/BP_SchematicManager.BP_SchematicManager_C",
+		saveformat.Property{Name: "mActiveSchematic", Type: "ObjectProperty", Value: testsave.Ref(path)},
+	)
+	progress, err := model.Progression(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active := progress.ActiveMilestone; active == nil || active.Path != path || active.Kind != model.SchematicMilestone || active.Tier != 3 {
+		t.Error("Active milestone:", active)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	players, err := model.Players(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 1 {
+		t.Fatal("Players:", len(players))
+	}
+
+	player := players[0]
+	if player.Name != "GIFF MANA" || player.Character == nil || player.Health != 100 {
+		t.Error("Invalid player:", player.Name, player.Character, player.Health)
+	}
+	if len(player.Identities) != 1 || player.Identities[0].Platform != model.PlatformSteam || player.Identities[0].ID != "76561198083442458" {
+		t.Error("Identities:", player.Identities)
+	}
+
+	items := make(map[string]int)
+	for _, item := range player.Items {
+		items[model.ClassName(item.ItemClass)] = item.Count
+	}
+	if items["Desc_IronScrew_C"] != 3200 || items["Desc_ModularFrame_C"] != 60 {
+		t.Error("Items:", items)
+	}
+
+	equipped := false
+	for _, slot := range player.Equipment {
+		if slot.Name == "Arms" && strings.Contains(slot.Equipped, "Equip_ObjectScanner") {
+			equipped = true
+		}
+	}
+	if len(player.Equipment) != 5 || !equipped {
+		t.Error("Equipment:", player.Equipment)
+	}
+	if len(player.Hotbars) == 0 || !slices.ContainsFunc(player.Hotbars[0], func(recipe string) bool {
+		return strings.Contains(recipe, "Recipe_PowerPoleMk1")
+	}) {
+		t.Error("Hotbars:", player.Hotbars)
+	}
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const playerStateClass = "BP_PlayerState_C"
+
+// Platform is the online service of a player identity, the values are the saved identity types.
+type Platform byte
+
+const (
+	PlatformEpic  Platform = 1
+	PlatformSteam Platform = 6
+)
+
+func (p Platform) String() string {
+	switch p {
+	case PlatformEpic:
+		return "Epic"
+	case PlatformSteam:
+		return "Steam"
+	}
+	return "Unknown(" + strconv.Itoa(int(p)) + ")"
+}
+
+// PlayerIdentity is an online account of a player.
+type PlayerIdentity struct {
+	Platform Platform
+	ID       string // SteamID64 for Steam, the hex account id otherwise
+}
+
+func newPlayerIdentity(identity readfields.Identity) PlayerIdentity {
+	id := PlayerIdentity{Platform: Platform(identity.Type)}
+	if id.Platform == PlatformSteam && len(identity.Data) == 8 {
+		id.ID = strconv.FormatUint(binary.LittleEndian.Uint64(identity.Data), 10)
+	} else {
+		id.ID = hex.EncodeToString(identity.Data)
+	}
+	return id
+}
+
+// EquipmentSlot is an equipment inventory of a player, like the arms or back slot.
+type EquipmentSlot struct {
+	Name      string // property name of the slot without the m prefix and suffix, e.g. Arms
+	Equipped  string // path of the equipment actor in use, empty when nothing is equipped
+	Inventory *Inventory
+}
+
+// Player is a player state with its character. Offline players keep their character.
+type Player struct {
+	State      *saveformat.Actor
+	Character  *saveformat.Actor // nil when the player has no character, e.g. before spawning
+	Name       string
+	UUID       string
+	Identities []PlayerIdentity
+	X, Y, Z    float32 // position of the character, or the last safe location without one
+	Health     float32 // 100 is full, full health is not saved
+	Inventory  *Inventory
+	Items      []ItemTotal // totals of the inventory by item, in slot order
+	Equipment  []EquipmentSlot
+	Hotbars    [][]string // recipe paths of the shortcuts of each hotbar, empty for other shortcuts
+}
+
+var equipmentSlots = []string{"Arms", "Back", "Legs", "Head", "Body"}
+
+// Players returns the players of the save in save order.
+func Players(save *saveformat.SaveIndex) ([]*Player, error) {
+	var players []*Player
+	for _, actor := range save.Actors {
+		if ClassName(actor.Header.TypePath) != playerStateClass {
+			continue
+		}
+		player, err := newPlayer(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+func newPlayer(save *saveformat.SaveIndex, state *saveformat.Actor) (*Player, error) {
+	props, err := state.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	player := &Player{State: state, Health: 100}
+	if info, err := props.Get("mClientIdentityInfo"); err == nil {
+		if info, ok := info.Value.(readfields.ClientIdentityInfo); ok {
+			player.UUID = info.UUID
+			for _, identity := range info.Identities {
+				player.Identities = append(player.Identities, newPlayerIdentity(identity))
+			}
+		}
+	}
+	if location, err := props.Get("mLastSafeCharacterLocation"); err == nil {
+		if v, ok := location.Value.(readfields.Vector); ok {
+			player.X, player.Y, player.Z = float32(v.X), float32(v.Y), float32(v.Z)
+		}
+	}
+	if player.Hotbars, err = hotbars(save, props); err != nil {
+		return nil, err
+	}
+
+	player.Character, _ = save.Actor(objectProperty(props, "mOwnedPawn").PathName)
+	if player.Character == nil {
+		return player, nil
+	}
+	if err := player.readCharacter(save); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+func (player *Player) readCharacter(save *saveformat.SaveIndex) error {
+	character := player.Character
+	props, err := character.DecodedProperties()
+	if err != nil {
+		return err
+	}
+
+	player.Name, _ = props.Str("mCachedPlayerName")
+	player.X, player.Y, player.Z = character.Header.PositionX, character.Header.PositionY, character.Header.PositionZ
+	if health := ComponentProperty(save, props, "mHealthComponent"); health != nil {
+		healthProps, err := health.DecodedProperties()
+		if err != nil {
+			return err
+		}
+		player.Health = floatProperty(healthProps, "mCurrentHealth", player.Health)
+	}
+
+	if player.Inventory, err = InventoryProperty(save, props, "mInventory"); err != nil {
+		return err
+	}
+	if player.Inventory != nil {
+		totals := make(map[string]int)
+		for _, stack := range player.Inventory.Stacks {
+			i, ok := totals[stack.ItemClass]
+			if !ok {
+				i = len(player.Items)
+				totals[stack.ItemClass] = i
+				player.Items = append(player.Items, ItemTotal{Owner: character, ItemClass: stack.ItemClass})
+			}
+			player.Items[i].Count += stack.Count
+		}
+	}
+
+	for _, name := range equipmentSlots {
+		component := ComponentProperty(save, props, "m"+name+"EquipmentSlot")
+		if component == nil {
+			continue
+		}
+		slot := EquipmentSlot{Name: name}
+		if slot.Inventory, err = NewInventory(component); err != nil {
+			return err
+		}
+		slotProps, err := component.DecodedProperties()
+		if err != nil {
+			return err
+		}
+		slot.Equipped = objectProperty(slotProps, "mEquipmentInSlot").PathName
+		player.Equipment = append(player.Equipment, slot)
+	}
+	return nil
+}
+
+func hotbars(save *saveformat.SaveIndex, props saveformat.Properties) ([][]string, error) {
+	var hotbars [][]string
+	for _, ref := range objectReferences(props, "mPlayerHotbars") {
+		var recipes []string
+		if hotbar, ok := save.Component(ref.PathName); ok {
+			hotbarProps, err := hotbar.DecodedProperties()
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range objectReferences(hotbarProps, "mShortcuts") {
+				recipe := ""
+				if shortcut, ok := save.Component(ref.PathName); ok {
+					shortcutProps, err := shortcut.DecodedProperties()
+					if err != nil {
+						return nil, err
+					}
+					recipe = objectProperty(shortcutProps, "mRecipeToActivate").PathName
+				}
+				recipes = append(recipes, recipe)
+			}
+		}
+		hotbars = append(hotbars, recipes)
+	}
+	return hotbars, nil
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const powerConnectionType = "/Script/FactoryGame.FGPowerConnectionComponent"
+
+// PowerInfo is the FGPowerInfoComponent of a building, values are in MW.
+type PowerInfo struct {
+	Component                 *saveformat.Component
+	TargetConsumption         float32
+	BaseProduction            float32
+	DynamicProductionCapacity float32
+	IsFullBlast               bool
+}
+
+func newPowerInfo(component *saveformat.Component) (*PowerInfo, error) {
+	props, err := component.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PowerInfo{
+		Component:                 component,
+		TargetConsumption:         floatProperty(props, "mTargetConsumption", 0),
+		BaseProduction:            floatProperty(props, "mBaseProduction", 0),
+		DynamicProductionCapacity: floatProperty(props, "mDynamicProductionCapacity", 0),
+		IsFullBlast:               boolProperty(props, "mIsFullBlast"),
+	}, nil
+}
+
+// PowerPole is a power pole, wall outlet or power tower.
+type PowerPole struct {
+	Building
+	Tier           int
+	MaxConnections int
+	Connections    []*saveformat.Component      // the FGPowerConnectionComponents of the pole
+	Wires          []saveformat.ObjectReference // wires attached to any of the connections
+}
+
+func NewPowerPole(save *saveformat.SaveIndex, actor *saveformat.Actor) (*PowerPole, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	pole := &PowerPole{
+		Building:       building,
+		Tier:           tier(building.Class),
+		MaxConnections: maxPowerConnections(building.Class),
+		Connections:    componentsOfType(actor, powerConnectionType),
+	}
+	for _, connection := range pole.Connections {
+		props, err := connection.DecodedProperties()
+		if err != nil {
+			return nil, err
+		}
+		wires, _ := props.Get("mWires")
+		refs, _ := wires.Value.([]saveformat.ObjectReference)
+		pole.Wires = append(pole.Wires, refs...)
+	}
+	return pole, nil
+}
+
+// maxPowerConnections returns the wire limit of a power pole class, wall outlets and towers have no tier limit.
+func maxPowerConnections(class string) int {
+	switch {
+	case strings.HasPrefix(class, "Build_PowerPoleWall"):
+		return 1
+	case strings.HasPrefix(class, "Build_PowerTower"):
+		return 2
+	}
+	return [...]int{4, 7, 10}[min(tier(class), 3)-1]
+}
+
+// componentsOfType returns the components of an actor with the given type path.
+func componentsOfType(actor *saveformat.Actor, typePath string) []*saveformat.Component {
+	var components []*saveformat.Component
+	for _, component := range actor.Components {
+		if component.Header.TypePath == typePath {
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// Wire is a power line between two power connections.
+type Wire struct {
+	Actor       *saveformat.Actor
+	Connections [2]saveformat.ObjectReference
+}
+
+// NewWire reads the connections of a power line, which are saved after its properties.
+func NewWire(actor *saveformat.Actor) (wire *Wire, err error) {
+	if _, err := actor.DecodedProperties(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("reading wire connections of %s: %v", actor.Header.Name, r)
+		}
+	}()
+
+	d := decoder.NewBytesDecoder(actor.Object.Trailing)
+	d.Uint32()
+	wire = &Wire{Actor: actor}
+	wire.Connections[0], wire.Connections[1] = readfields.ReadObjectReference(d), readfields.ReadObjectReference(d)
+	return wire, nil
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const (
+	somersloopClass = "Desc_WAT1_C"
+	powerShardClass = "Desc_CrystalShard_C"
+)
+
+// ProductionBuilding is a manufacturer such as a constructor, refinery or quantum encoder.
+type ProductionBuilding struct {
+	Building
+	Recipe           string  // path of the recipe class, empty when no recipe is set
+	Potential        float32 // clock speed, 1 is 100%
+	PendingPotential float32
+	ProductionBoost  float32 // production amplification from somersloops, 1 is no boost
+	PendingBoost     float32 // amplification the building changes to, only the pending value is saved when idle
+	PowerShards      int
+	Somersloops      int
+	Productivity     float32 // fraction of the last measurement spent producing
+	IsProducing      bool
+	InputInventory   *Inventory
+	OutputInventory  *Inventory
+}
+
+func NewProductionBuilding(save *saveformat.SaveIndex, actor *saveformat.Actor) (*ProductionBuilding, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	props := building.Properties
+	p := &ProductionBuilding{
+		Building:         building,
+		Recipe:           objectProperty(props, "mCurrentRecipe").PathName,
+		Potential:        floatProperty(props, "mCurrentPotential", 1),
+		PendingPotential: floatProperty(props, "mPendingPotential", 1),
+		ProductionBoost:  floatProperty(props, "mCurrentProductionBoost", 1),
+		PendingBoost:     floatProperty(props, "mPendingProductionBoost", 1),
+		Productivity:     productivity(props),
+		IsProducing:      boolProperty(props, "mIsProducing"),
+	}
+
+	if p.PowerShards, p.Somersloops, err = potentialItems(save, props); err != nil {
+		return nil, err
+	}
+	if p.InputInventory, err = InventoryProperty(save, props, "mInputInventory"); err != nil {
+		return nil, err
+	}
+	if p.OutputInventory, err = InventoryProperty(save, props, "mOutputInventory"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Generator is a power generator, fueled or not.
+type Generator struct {
+	Building
+	Potential     float32
+	PowerShards   int
+	Somersloops   int
+	FuelInventory *Inventory // nil for generators without fuel
+}
+
+func NewGenerator(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Generator, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generator{
+		Building:  building,
+		Potential: floatProperty(building.Properties, "mCurrentPotential", 1),
+	}
+	if g.PowerShards, g.Somersloops, err = potentialItems(save, building.Properties); err != nil {
+		return nil, err
+	}
+	if g.FuelInventory, err = InventoryProperty(save, building.Properties, "mFuelInventory"); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Extractor is a miner, pump or resource well extractor.
+type Extractor struct {
+	Building
+	Tier            int
+	Resource        saveformat.ObjectReference // the extracted resource node, empty for water pumps
+	Potential       float32
+	PowerShards     int
+	Productivity    float32
+	IsProducing     bool
+	OutputInventory *Inventory
+}
+
+func NewExtractor(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Extractor, error) {
+	building, err := NewBuilding(save, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	props := building.Properties
+	e := &Extractor{
+		Building:     building,
+		Tier:         tier(building.Class),
+		Resource:     objectProperty(props, "mExtractableResource"),
+		Potential:    floatProperty(props, "mCurrentPotential", 1),
+		Productivity: productivity(props),
+		IsProducing:  boolProperty(props, "mIsProducing"),
+	}
+	if e.PowerShards, _, err = potentialItems(save, props); err != nil {
+		return nil, err
+	}
+	if e.OutputInventory, err = InventoryProperty(save, props, "mOutputInventory"); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func productivity(props saveformat.Properties) float32 {
+	duration := floatProperty(props, "mLastProductivityMeasurementDuration", 0)
+	if duration == 0 {
+		return 0
+	}
+	return floatProperty(props, "mLastProductivityMeasurementProduceDuration", 0) / duration
+}
+
+// potentialItems counts the power shards and somersloops slotted into a building.
+func potentialItems(save *saveformat.SaveIndex, props saveformat.Properties) (shards int, somersloops int, err error) {
+	inventory, err := InventoryProperty(save, props, "mInventoryPotential")
+	if inventory == nil {
+		return 0, 0, err
+	}
+
+	for _, stack := range inventory.Stacks {
+		switch {
+		case strings.HasSuffix(stack.ItemClass, powerShardClass):
+			shards += stack.Count
+		case strings.HasSuffix(stack.ItemClass, somersloopClass):
+			somersloops += stack.Count
+		}
+	}
+	return shards, somersloops, nil
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+type SchematicKind int
+
+const (
+	SchematicOther     SchematicKind = iota
+	SchematicTutorial                // HUB upgrades
+	SchematicMilestone               // milestones of the HUB tiers
+	SchematicResearch                // MAM research
+	SchematicAlternate               // alternate recipes from hard drives
+	SchematicShop                    // AWESOME shop purchases
+)
+
+// Schematic is a purchased schematic.
+type Schematic struct {
+	Path string
+	Kind SchematicKind
+	Name string // display name from the embedded table, see SchematicName
+	Tier int    // milestones only
+	Tree string // research tree of MAM research
+}
+
+// Progress is the unlock progression of a save.
+type Progress struct {
+	Schematics    []Schematic // all purchased schematics in save order
+	Tiers         []int       // tiers with a purchased milestone, 0 for the HUB upgrades
+	Milestones    []Schematic
+	Research      []Schematic
+	Alternates    []Schematic
+	ShopPurchases []Schematic
+	ResearchTrees []string // unlocked MAM research trees
+
+	ActiveMilestone *Schematic // milestone selected in the HUB, nil when none is
+
+	GamePhase       string // path of the current space elevator phase
+	Phase           int
+	IsGameCompleted bool
+}
+
+// Progression reads the purchased schematics, research trees and space elevator phase from the
+// schematic, research and game phase managers. Research tree names are derived from class names,
+// not the in-game display names.
+func Progression(save *saveformat.SaveIndex) (*Progress, error) {
+	var progress Progress
+	for _, actor := range save.Actors {
+		var props saveformat.Properties
+		switch ClassName(actor.Header.TypePath) {
+		case "BP_SchematicManager_C", "BP_ResearchManager_C", "BP_GamePhaseManager_C":
+			var err error
+			if props, err = actor.DecodedProperties(); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		for _, ref := range objectReferences(props, "mPurchasedSchematics") {
+			progress.addSchematic(newSchematic(ref.PathName))
+		}
+		if active := objectProperty(props, "mActiveSchematic").PathName; active != "" {
+			schematic := newSchematic(active)
+			progress.ActiveMilestone = &schematic
+		}
+		for _, ref := range objectReferences(props, "mUnlockedResearchTrees") {
+			progress.ResearchTrees = append(progress.ResearchTrees, readableName(ClassName(ref.PathName), "BPD_ResearchTree_"))
+		}
+		if phase := objectProperty(props, "mCurrentGamePhase").PathName; phase != "" {
+			progress.GamePhase = phase
+			progress.Phase = trailingNumber(ClassName(phase))
+			progress.IsGameCompleted = boolProperty(props, "mIsGameCompleted")
+		}
+	}
+	slices.Sort(progress.Tiers)
+	return &progress, nil
+}
+
+func (progress *Progress) addSchematic(schematic Schematic) {
+	progress.Schematics = append(progress.Schematics, schematic)
+	switch schematic.Kind {
+	case SchematicTutorial, SchematicMilestone:
+		if !slices.Contains(progress.Tiers, schematic.Tier) {
+			progress.Tiers = append(progress.Tiers, schematic.Tier)
+		}
+		if schematic.Kind == SchematicMilestone {
+			progress.Milestones = append(progress.Milestones, schematic)
+		}
+	case SchematicResearch:
+		progress.Research = append(progress.Research, schematic)
+	case SchematicAlternate:
+		progress.Alternates = append(progress.Alternates, schematic)
+	case SchematicShop:
+		progress.ShopPurchases = append(progress.ShopPurchases, schematic)
+	}
+}
+
+// newSchematic classifies a schematic by its folder, e.g.
+// /Game/FactoryGame/Schematics/Research/Caterium_RS/Research_Caterium_3.Research_Caterium_3_C.
+func newSchematic(path string) Schematic {
+	schematic := Schematic{Path: path, Name: SchematicName(path)}
+	class := ClassName(path)
+	folders := strings.Split(path[:max(strings.LastIndex(path, "/"), 0)], "/")
+	folder := ""
+	if i := slices.Index(folders, "Schematics"); i >= 0 && i+1 < len(folders) {
+		folder = folders[i+1]
+	}
+
+	switch {
+	case folder == "Tutorial" || class == "Schematic_StartingRecipes_C":
+		schematic.Kind = SchematicTutorial
+	case folder == "Progression" && strings.HasPrefix(class, "Schematic_"):
+		// Schematic_5-4-1_C is the first extra milestone after milestone 4 of tier 5
+		schematic.Kind = SchematicMilestone
+		tier, _, _ := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(class, "Schematic_"), "_C"), "-")
+		schematic.Tier, _ = strconv.Atoi(tier)
+	case folder == "Research":
+		schematic.Kind = SchematicResearch
+		if tree := folders[len(folders)-1]; tree != folder {
+			schematic.Tree = readableName(tree, "")
+		}
+	case folder == "Alternate":
+		schematic.Kind = SchematicAlternate
+	case folder == "ResourceSink":
+		schematic.Kind = SchematicShop
+	}
+	return schematic
+}
+
+// readableName turns a class name into words, e.g. BPD_ResearchTree_AlienOrganisms_C without its
+// prefix becomes "Alien Organisms".
+func readableName(class string, prefix string) string {
+	name := strings.TrimPrefix(strings.TrimSuffix(class, "_C"), prefix)
+	name = strings.TrimSuffix(name, "_RS") // research tree folders
+
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, r := runes[i-1], runes[i]
+			upperAfterLower := unicode.IsUpper(r) && !unicode.IsUpper(prev)
+			acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			digitAfterLetter := unicode.IsDigit(r) && !unicode.IsDigit(prev)
+			if upperAfterLower || acronymEnd || digitAfterLetter {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return strings.Join(words, " ")
+}
+
+func objectReferences(props saveformat.Properties, name string) []saveformat.ObjectReference {
+	elements, _ := props.Array(name)
+	refs := make([]saveformat.ObjectReference, 0, len(elements))
+	for _, element := range elements {
+		if ref, ok := element.(saveformat.ObjectReference); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// trailingNumber returns the number at the end of a name, e.g. 7 for GP_Project_Assembly_Phase_7.
+func trailingNumber(name string) int {
+	n, _ := strconv.Atoi(name[strings.LastIndexFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })+1:])
+	return n
+}
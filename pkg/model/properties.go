@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/decoder"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// floatProperty returns the value of a float property, or def when it is not saved.
+func floatProperty(props saveformat.Properties, name string, def float32) float32 {
+	value, err := props.Float(name)
+	if err != nil {
+		return def
+	}
+	return float32(value)
+}
+
+func intProperty(props saveformat.Properties, name string) int {
+	value, _ := props.Int(name)
+	return int(value)
+}
+
+func boolProperty(props saveformat.Properties, name string) bool {
+	value, _ := props.Bool(name)
+	return value
+}
+
+func objectProperty(props saveformat.Properties, name string) saveformat.ObjectReference {
+	ref, _ := props.Object(name)
+	return ref
+}
+
+// ActorProperty resolves an object property that references an actor, nil when it is not saved
+// or the actor is not in the save.
+func ActorProperty(save *saveformat.SaveIndex, props saveformat.Properties, name string) *saveformat.Actor {
+	ref := objectProperty(props, name)
+	if ref.PathName == "" {
+		return nil
+	}
+	actor, _ := save.Actor(ref.PathName)
+	return actor
+}
+
+// ComponentProperty resolves an object property that references a component, nil when it is not
+// saved or the component is not in the save.
+func ComponentProperty(save *saveformat.SaveIndex, props saveformat.Properties, name string) *saveformat.Component {
+	ref := objectProperty(props, name)
+	if ref.PathName == "" {
+		return nil
+	}
+	component, _ := save.Component(ref.PathName)
+	return component
+}
+
+// textProperty returns the display string of a text property, empty when it is not saved.
+func textProperty(props saveformat.Properties, name string) (string, error) {
+	prop, err := props.Get(name)
+	if err != nil || prop.Raw == nil {
+		return "", nil
+	}
+	text, err := readfields.ReadText(decoder.NewBytesDecoder(prop.Raw))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return text, nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+)
+
+// schematics.json maps schematic classes to their in-game display names. Regenerate it from the
+// Docs.json of the game after an update.
+//
+//go:generate go run genschematics.go -docs ${SATISFACTORY_DOCS} -out schematics.json
+//go:embed schematics.json
+var schematicsJSON []byte
+
+var schematicNames map[string]string
+
+func init() {
+	var data struct {
+		Schematics []struct {
+			Class string `json:"class"`
+			Name  string `json:"name"`
+		} `json:"schematics"`
+	}
+	if err := json.Unmarshal(schematicsJSON, &data); err != nil {
+		panic("parsing embedded schematic names: " + err.Error())
+	}
+
+	schematicNames = make(map[string]string, len(data.Schematics))
+	for _, schematic := range data.Schematics {
+		schematicNames[schematic.Class] = schematic.Name
+	}
+}
+
+// SchematicName returns the display name of a schematic by class name or full path, or its class
+// name when it is not in the embedded table.
+func SchematicName(class string) string {
+	if name, ok := schematicNames[ClassName(class)]; ok {
+		return name
+	}
+	return ClassName(class)
+}
@@ -0,0 +1,35 @@
+package model
+
+import "github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+
+// TrainClass is the class of the actor grouping the locomotives and wagons of a train.
+const TrainClass = "BP_Train_C"
+
+// Train is a train, its vehicles are the locomotives and wagons between the first and last vehicle.
+type Train struct {
+	Actor         *saveformat.Actor
+	Name          string
+	FirstVehicle  *saveformat.Actor
+	LastVehicle   *saveformat.Actor
+	TimeTable     *saveformat.Actor // nil when the train has no timetable
+	IsSelfDriving bool
+}
+
+func NewTrain(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Train, error) {
+	props, err := actor.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	train := &Train{
+		Actor:         actor,
+		IsSelfDriving: boolProperty(props, "mIsSelfDrivingEnabled"),
+	}
+	if train.Name, err = textProperty(props, "mTrainName"); err != nil {
+		return nil, err
+	}
+	train.FirstVehicle, _ = save.Actor(objectProperty(props, "FirstVehicle").PathName)
+	train.LastVehicle, _ = save.Actor(objectProperty(props, "LastVehicle").PathName)
+	train.TimeTable, _ = save.Actor(objectProperty(props, "TimeTable").PathName)
+	return train, nil
+}
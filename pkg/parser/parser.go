@@ -3,12 +3,55 @@ package parser
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
 	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
-func ParseSaveFile(saveFileName string) *saveformat.SaveFileBody {
+type Option func(*readsave.Options)
+
+// WithParallelLevels decodes the levels of the save concurrently, at the cost of
+// holding the whole decompressed body in memory. Small saves are still decoded
+// sequentially, as the goroutines cost more than they save. Walk does not accept it.
+func WithParallelLevels() Option {
+	return func(o *readsave.Options) { o.ParallelLevels = true }
+}
+
+// WithClassFilter decodes only the actors whose type path is accepted by filter,
+// together with their components. All other objects are skipped without decoding.
+func WithClassFilter(filter func(typePath string) bool) Option {
+	return func(o *readsave.Options) { o.ClassFilter = filter }
+}
+
+// WithClasses decodes only the actors of the given classes and their components.
+// A class is given by name, e.g. "Build_TrainStation_C", or by its full type path.
+func WithClasses(classes ...string) Option {
+	allowed := make(map[string]bool, len(classes))
+	for _, class := range classes {
+		allowed[class] = true
+	}
+
+	return WithClassFilter(func(typePath string) bool {
+		return allowed[typePath] || allowed[typePath[strings.LastIndexByte(typePath, '.')+1:]]
+	})
+}
+
+// WithLazyProperties keeps the properties of each object undecoded until
+// DecodeProperties is called on the actor or component object.
+func WithLazyProperties() Option {
+	return func(o *readsave.Options) { o.LazyProperties = true }
+}
+
+func newOptions(opts []Option) readsave.Options {
+	var options readsave.Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+func ParseSaveFile(saveFileName string, opts ...Option) *saveformat.SaveFileBody {
 	file, err := os.Open(saveFileName)
 	if err != nil {
 		fmt.Println("Error:", err)
@@ -16,6 +59,6 @@ func ParseSaveFile(saveFileName string) *saveformat.SaveFileBody {
 	}
 	defer file.Close()
 
-	body := readsave.ReadSave(file)
+	body := readsave.ReadSave(file, newOptions(opts))
 	return body
 }
@@ -1,17 +1,95 @@
 package parser_test
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/writesave"
 	. "github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
 )
 
-func BenchmarkParser(b *testing.B) {
+// benchmarkSave is the large benchmark save when it is present, the test save otherwise.
+func benchmarkSave() string {
 	save := "testdata/test_benchmark.sav"
-	// save := "testdata/test_creative_v1.1_exp.sav"
+	if _, err := os.Stat(save); err != nil {
+		return "testdata/test_creative_v1.1_exp.sav"
+	}
+	return save
+}
+
+func BenchmarkParser(b *testing.B) {
+	save := benchmarkSave()
 	b.Run(save, func(b *testing.B) {
+		b.ReportAllocs()
 		for i := 0; i < b.N; i++ {
 			ParseSaveFile(save)
 		}
 	})
 }
+
+func BenchmarkParserParallelLevels(b *testing.B) {
+	save := benchmarkSave()
+	b.Run(save, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ParseSaveFile(save, WithParallelLevels())
+		}
+	})
+}
+
+func BenchmarkParserClassFilter(b *testing.B) {
+	save := benchmarkSave()
+	b.Run(save, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ParseSaveFile(save, WithClasses("BP_PlayerState_C"))
+		}
+	})
+}
+
+// manyLevelsBody writes the test save with copies of its persistent level as sub levels, like a
+// late game save with its objects spread over many streaming levels.
+func manyLevelsBody(b *testing.B, copies int) ([]byte, uint32) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+	header, data := readsave.ReadBody(file)
+	body := readsave.ParseBody(data, header.SaveVersion, readsave.Options{})
+
+	persistent := body.Levels[len(body.Levels)-1]
+	levels := slices.Clone(body.Levels[:len(body.Levels)-1])
+	for i := range copies {
+		level := persistent
+		level.Name = fmt.Sprintf("Benchmark_Level_%d", i)
+		level.CollectablesLevelCount, level.CollectablesLevelName = 0, ""
+		level.SaveVersion = header.SaveVersion
+		levels = append(levels, level)
+	}
+	body.Levels = append(levels, persistent)
+	return writesave.WriteBody(body, header.SaveVersion), header.SaveVersion
+}
+
+// BenchmarkParseBodyManyLevels compares sequential and parallel level decoding of a body with
+// 32 copies of the persistent level, run it with e.g. -cpu 1,4,8.
+func BenchmarkParseBodyManyLevels(b *testing.B) {
+	data, version := manyLevelsBody(b, 32)
+	minLevels, minBytes := readsave.MinParallelLevels, readsave.MinParallelBytes
+	readsave.MinParallelLevels, readsave.MinParallelBytes = 0, 0
+	defer func() { readsave.MinParallelLevels, readsave.MinParallelBytes = minLevels, minBytes }()
+
+	for _, parallel := range []bool{false, true} {
+		b.Run(fmt.Sprintf("parallel=%t", parallel), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				readsave.ParseBody(data, version, readsave.Options{ParallelLevels: parallel})
+			}
+		})
+	}
+}
@@ -1,10 +1,20 @@
 package parser_test
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"unsafe"
 
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/writesave"
 	. "github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
 )
 
 func testReadSaveFile(file string, t *testing.T) {
@@ -35,3 +45,163 @@ func TestReadSaveFile(t *testing.T) {
 		testReadSaveFile(save, t)
 	}
 }
+
+func TestParallelLevels(t *testing.T) {
+	// the test save is below the thresholds for parallel decoding
+	minLevels, minBytes := readsave.MinParallelLevels, readsave.MinParallelBytes
+	readsave.MinParallelLevels, readsave.MinParallelBytes = 0, 0
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+	defer func() { readsave.MinParallelLevels, readsave.MinParallelBytes = minLevels, minBytes }()
+
+	saveFile := filepath.Join("testdata", "test_creative_v1.1_exp.sav")
+	sequential := ParseSaveFile(saveFile)
+	parallel := ParseSaveFile(saveFile, WithParallelLevels())
+
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Error("Parallel level decoding differs from sequential decoding")
+	}
+}
+
+func TestInternedTypePaths(t *testing.T) {
+	body := ParseSaveFile(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+
+	seen := make(map[string]*byte)
+	for _, level := range body.Levels {
+		for _, header := range level.ActorHeaders {
+			data := unsafe.StringData(header.TypePath)
+			if first, ok := seen[header.TypePath]; ok && first != data {
+				t.Fatal("Type path not interned:", header.TypePath)
+			}
+			seen[header.TypePath] = data
+		}
+	}
+}
+
+func TestClassFilter(t *testing.T) {
+	saveFile := filepath.Join("testdata", "test_creative_v1.1_exp.sav")
+	full := ParseSaveFile(saveFile)
+	// components of dropped actors are dropped too, even when their own class is accepted
+	filtered := ParseSaveFile(saveFile, WithClassFilter(func(typePath string) bool {
+		return strings.HasSuffix(typePath, ".BP_PlayerState_C") || strings.HasSuffix(typePath, ".FGPowerInfoComponent")
+	}))
+
+	want := 0
+	for _, level := range full.Levels {
+		for _, header := range level.ActorHeaders {
+			if strings.HasSuffix(header.TypePath, ".BP_PlayerState_C") {
+				want++
+			}
+		}
+	}
+
+	got := 0
+	for _, level := range filtered.Levels {
+		if len(level.ActorHeaders) != len(level.ActorObjects) || len(level.ComponentHeaders) != len(level.ComponentObjects) {
+			t.Fatal(level.Name, "headers and objects are not aligned")
+		}
+		if int(level.HeaderCount) != len(level.HeaderTypes) || level.ObjectCount != level.HeaderCount {
+			t.Error(level.Name, "counts not updated:", level.HeaderCount, level.ObjectCount, len(level.HeaderTypes))
+		}
+
+		kept := make(map[string]bool)
+		for _, header := range level.ActorHeaders {
+			if !strings.HasSuffix(header.TypePath, ".BP_PlayerState_C") {
+				t.Error("Unexpected actor:", header.TypePath)
+			}
+			kept[header.Name] = true
+			got++
+		}
+		// components can be nested in other components of the kept actor
+		for _, header := range level.ComponentHeaders {
+			kept[header.Name] = true
+		}
+		for _, header := range level.ComponentHeaders {
+			if !kept[header.ParentActorName] {
+				t.Error("Component without kept parent:", header.Name)
+			}
+		}
+	}
+
+	if want == 0 || got != want {
+		t.Error("Filtered actors:", got, "want", want)
+	}
+}
+
+func TestClassFilterSizes(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	header, data := readsave.ReadBody(file)
+	body := readsave.ParseBody(data, header.SaveVersion, readsave.Options{})
+
+	// an actor with components nested deeper than any in the test save
+	persistent := testsave.Level{LevelData: body.Levels[len(body.Levels)-1]}
+	actorObject, componentObject := persistent.ActorObjects[0], persistent.ComponentObjects[0]
+	none := []saveformat.Property{{Name: "None"}}
+	persistent.AddActor("Persistent_Level:PersistentLevel.Deep", "Deep.Deep_C")
+	persistent.ActorObjects[len(persistent.ActorObjects)-1] = saveformat.ActorObject{SaveVersion: actorObject.SaveVersion, Properties: none, Trailing: actorObject.Trailing}
+	parent := "Persistent_Level:PersistentLevel.Deep"
+	for i := range 12 {
+		name := fmt.Sprintf("%s.Component%d", parent, i)
+		persistent.AddComponent(name, "/Script/Engine.SceneComponent", parent)
+		persistent.ComponentObjects[len(persistent.ComponentObjects)-1] = saveformat.ComponentObject{SaveVersion: componentObject.SaveVersion, Properties: none, Trailing: componentObject.Trailing}
+		parent = name
+	}
+	body.Levels[len(body.Levels)-1] = persistent.LevelData
+	data = writesave.WriteBody(body, header.SaveVersion)
+
+	filtered := readsave.ParseBody(data, header.SaveVersion, readsave.Options{ClassFilter: func(typePath string) bool {
+		return strings.HasSuffix(typePath, ".BP_PlayerState_C") || typePath == "/Game/Deep.Deep_C"
+	}})
+	deep := filtered.Levels[len(filtered.Levels)-1]
+	if len(deep.ComponentHeaders) < 12 || deep.ComponentHeaders[len(deep.ComponentHeaders)-1].Name != parent {
+		t.Error("Nested components were dropped:", len(deep.ComponentHeaders))
+	}
+
+	// the sizes of the filtered levels match the sizes they are written with
+	written := writesave.WriteBody(filtered, header.SaveVersion)
+	reread := readsave.ParseBody(written, header.SaveVersion, readsave.Options{})
+	for i, level := range filtered.Levels {
+		if level.Size != reread.Levels[i].Size || level.ObjectSize != reread.Levels[i].ObjectSize {
+			t.Error(level.Name, "sizes:", level.Size, level.ObjectSize, "written", reread.Levels[i].Size, reread.Levels[i].ObjectSize)
+		}
+	}
+}
+
+func TestLazyProperties(t *testing.T) {
+	saveFile := filepath.Join("testdata", "test_creative_v1.1_exp.sav")
+	full := ParseSaveFile(saveFile)
+	lazy := ParseSaveFile(saveFile, WithLazyProperties())
+
+	for i, level := range lazy.Levels {
+		for j := range level.ActorObjects {
+			actor := &level.ActorObjects[j]
+			if actor.Lazy == nil || actor.Properties != nil {
+				t.Fatal("Actor properties decoded eagerly:", level.ActorHeaders[j].Name)
+			}
+
+			props, err := actor.DecodeProperties()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := full.Levels[i].ActorObjects[j]
+			if !reflect.DeepEqual(props, want.Properties) || !reflect.DeepEqual(actor.Trailing, want.Trailing) {
+				t.Fatal("Lazy actor properties differ:", level.ActorHeaders[j].Name)
+			}
+		}
+
+		for j := range level.ComponentObjects {
+			component := &level.ComponentObjects[j]
+			props, err := component.DecodeProperties()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := full.Levels[i].ComponentObjects[j]
+			if !reflect.DeepEqual(props, want.Properties) || !reflect.DeepEqual(component.Trailing, want.Trailing) {
+				t.Fatal("Lazy component properties differ:", level.ComponentHeaders[j].Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/writesave"
+)
+
+// RoundTripError describes the first byte where the re-serialized body differs from the original.
+type RoundTripError struct {
+	Offset int64
+	Path   []string // level, object and property path of the differing byte
+}
+
+func (e *RoundTripError) Error() string {
+	path := strings.Join(e.Path, " > ")
+	if path == "" {
+		path = "end of body"
+	}
+	return fmt.Sprintf("round trip differs at offset %d (0x%x): %s", e.Offset, e.Offset, path)
+}
+
+// RoundTrip parses a save file, re-serializes the uncompressed body and compares it
+// byte for byte with the decompressed original. A *RoundTripError is returned on the first difference.
+func RoundTrip(saveFileName string) error {
+	file, err := os.Open(saveFileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	header, original := readsave.ReadBody(file)
+	body := readsave.ParseBody(original, header.SaveVersion, readsave.Options{})
+	written := writesave.WriteBody(body, header.SaveVersion)
+
+	offset := firstDifference(original, written)
+	if offset < 0 {
+		return nil
+	}
+
+	return &RoundTripError{
+		Offset: offset,
+		Path:   writesave.PathAt(body, header.SaveVersion, offset),
+	}
+}
+
+func firstDifference(a, b []byte) int64 {
+	n := min(len(a), len(b))
+	for i := range n {
+		if a[i] != b[i] {
+			return int64(i)
+		}
+	}
+	if len(a) != len(b) {
+		return int64(n)
+	}
+	return -1
+}
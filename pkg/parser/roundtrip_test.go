@@ -0,0 +1,137 @@
+package parser_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/writesave"
+	. "github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestRoundTrip(t *testing.T) {
+	entries, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sav" {
+			continue
+		}
+		if err := RoundTrip(filepath.Join("testdata", entry.Name())); err != nil {
+			t.Error(entry.Name(), err)
+		}
+	}
+}
+
+func TestRoundTripKeepsUnusualValues(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	header, data := readsave.ReadBody(file)
+	body := readsave.ParseBody(data, header.SaveVersion, readsave.Options{})
+
+	persistent := &body.Levels[len(body.Levels)-1]
+	persistent.HasCollectables, persistent.CollectablesLevelCount, persistent.CollectablesLevelName = true, 2, ""
+	guid := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	actor := -1
+	for i := range persistent.ActorObjects {
+		props := persistent.ActorObjects[i].Properties
+		for j := range props {
+			if props[j].Type == "StructProperty" {
+				props[j].GUID, actor = guid, i
+				break
+			}
+		}
+		if actor >= 0 {
+			break
+		}
+	}
+	if actor < 0 {
+		t.Fatal("No actor with a struct property")
+	}
+
+	written := writesave.WriteBody(body, header.SaveVersion)
+	reread := readsave.ParseBody(written, header.SaveVersion, readsave.Options{})
+	level := reread.Levels[len(reread.Levels)-1]
+	if level.CollectablesLevelCount != 2 || len(level.Collectables) != len(persistent.Collectables) {
+		t.Error("Collectables level count:", level.CollectablesLevelCount, "collectables:", len(level.Collectables))
+	}
+	if !reflect.DeepEqual(level.ActorObjects[actor].Properties, persistent.ActorObjects[actor].Properties) {
+		t.Error("Struct GUID was not written back")
+	}
+}
+
+func TestRoundTripKeepsArrayStructTrailingBytes(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	header, data := readsave.ReadBody(file)
+	body := readsave.ParseBody(data, header.SaveVersion, readsave.Options{})
+
+	persistent := &body.Levels[len(body.Levels)-1]
+	found := false
+	for i := range persistent.ComponentObjects {
+		props := persistent.ComponentObjects[i].Properties
+		for j := range props {
+			if v, ok := props[j].Value.(saveformat.ArrayStructProperty); ok && len(v.Value) > 0 {
+				v.Trailing = []byte{1, 2, 3}
+				props[j].Value, found = v, true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		t.Fatal("No component with a struct array")
+	}
+
+	written := writesave.WriteBody(body, header.SaveVersion)
+	reread := readsave.ParseBody(written, header.SaveVersion, readsave.Options{})
+	if rewritten := writesave.WriteBody(reread, header.SaveVersion); !bytes.Equal(rewritten, written) {
+		t.Error("Trailing bytes of a struct array were not kept")
+	}
+}
+
+func TestRoundTripKeepsStringEncodings(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	header, data := readsave.ReadBody(file)
+	body := readsave.ParseBody(data, header.SaveVersion, readsave.Options{})
+
+	// a non-ASCII string saved as UTF-8 and an ASCII string saved as UTF-16
+	body.LevelGroupingGrids[0].GridName = "Grille nº1"
+	body.LevelGroupingGrids[1].GridName = "Grid2"
+	body.StringEncodings["Grille nº1"] = saveformat.EncodingUTF8
+	body.StringEncodings["Grid2"] = saveformat.EncodingUTF16
+
+	written := writesave.WriteBody(body, header.SaveVersion)
+	if !bytes.Contains(written, []byte("\x0c\x00\x00\x00Grille nº1\x00")) {
+		t.Error("UTF-8 string was not written as UTF-8")
+	}
+	if !bytes.Contains(written, []byte("\xfa\xff\xff\xffG\x00r\x00i\x00d\x002\x00\x00\x00")) {
+		t.Error("ASCII string was not written as UTF-16")
+	}
+
+	reread := readsave.ParseBody(written, header.SaveVersion, readsave.Options{ParallelLevels: true})
+	if reread.LevelGroupingGrids[0].GridName != "Grille nº1" || reread.StringEncodings["Grid2"] != saveformat.EncodingUTF16 {
+		t.Error("Encodings:", reread.StringEncodings)
+	}
+	if rewritten := writesave.WriteBody(reread, header.SaveVersion); !bytes.Equal(rewritten, written) {
+		t.Error("String encodings were not kept")
+	}
+}
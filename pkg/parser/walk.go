@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// Visitor receives the header, levels and objects of a save while Walk reads it.
+// Objects are dropped by the reader after their callback, so a visitor that does
+// not keep them walks any save in constant memory.
+type Visitor = readsave.Visitor
+
+// VisitorFuncs is a Visitor built from optional callbacks, nil callbacks are skipped.
+type VisitorFuncs struct {
+	Header     func(header *saveformat.SaveFileHeader)
+	LevelStart func(level *saveformat.LevelData)
+	Actor      func(header *saveformat.ActorHeader, actor *saveformat.ActorObject)
+	Component  func(header *saveformat.ComponentHeader, component *saveformat.ComponentObject)
+	LevelEnd   func(level *saveformat.LevelData)
+}
+
+func (f VisitorFuncs) OnHeader(header *saveformat.SaveFileHeader) {
+	if f.Header != nil {
+		f.Header(header)
+	}
+}
+
+func (f VisitorFuncs) OnLevelStart(level *saveformat.LevelData) {
+	if f.LevelStart != nil {
+		f.LevelStart(level)
+	}
+}
+
+func (f VisitorFuncs) OnActor(header *saveformat.ActorHeader, actor *saveformat.ActorObject) {
+	if f.Actor != nil {
+		f.Actor(header, actor)
+	}
+}
+
+func (f VisitorFuncs) OnComponent(header *saveformat.ComponentHeader, component *saveformat.ComponentObject) {
+	if f.Component != nil {
+		f.Component(header, component)
+	}
+}
+
+func (f VisitorFuncs) OnLevelEnd(level *saveformat.LevelData) {
+	if f.LevelEnd != nil {
+		f.LevelEnd(level)
+	}
+}
+
+// Walk streams the save from r to v without building the SaveFileBody.
+// Levels are always read in order, WithParallelLevels is rejected.
+func Walk(r io.Reader, v Visitor, opts ...Option) (err error) {
+	options := newOptions(opts)
+	if options.ParallelLevels {
+		return errors.New("walking save file: levels can not be read in parallel while streaming")
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("walking save file: %v", p)
+		}
+	}()
+
+	readsave.Walk(r, v, options)
+	return nil
+}
@@ -0,0 +1,116 @@
+package parser_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+	"unsafe"
+
+	. "github.com/Maurits825/satisfactory-savefile-parser/pkg/parser"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestWalk(t *testing.T) {
+	saveFile := filepath.Join("testdata", "test_creative_v1.1_exp.sav")
+	body := ParseSaveFile(saveFile)
+
+	file, err := os.Open(saveFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	levels, actors, components := 0, 0, 0
+	err = Walk(file, VisitorFuncs{
+		LevelEnd:  func(level *saveformat.LevelData) { levels++ },
+		Actor:     func(header *saveformat.ActorHeader, actor *saveformat.ActorObject) { actors++ },
+		Component: func(header *saveformat.ComponentHeader, component *saveformat.ComponentObject) { components++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantActors, wantComponents := 0, 0
+	for _, level := range body.Levels {
+		wantActors += len(level.ActorObjects)
+		wantComponents += len(level.ComponentObjects)
+	}
+
+	if levels != len(body.Levels) {
+		t.Error("Walk levels:", levels, "want", len(body.Levels))
+	}
+	if actors != wantActors || components != wantComponents {
+		t.Error("Walk objects:", actors, components, "want", wantActors, wantComponents)
+	}
+}
+
+func TestWalkCorruptBody(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt := bytes.Clone(data)
+	for i := len(corrupt) / 2; i < len(corrupt)/2+64; i++ {
+		corrupt[i] ^= 0xff
+	}
+
+	for name, save := range map[string][]byte{
+		"truncated": data[:len(data)/2],
+		"corrupt":   corrupt,
+	} {
+		goroutines := runtime.NumGoroutine()
+		if err := Walk(bytes.NewReader(save), VisitorFuncs{}); err == nil {
+			t.Error(name, "save was walked without error")
+		}
+
+		// the decompression workers must stop once the walk returns
+		for i := 0; runtime.NumGoroutine() > goroutines; i++ {
+			if i == 100 {
+				t.Fatal(name, "goroutines leaked:", runtime.NumGoroutine()-goroutines)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestWalkDoesNotIntern(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// an interner would keep every string of the walk alive until it ends
+	seen := make(map[string]*byte)
+	shared := 0
+	err = Walk(file, VisitorFuncs{
+		Actor: func(header *saveformat.ActorHeader, actor *saveformat.ActorObject) {
+			data := unsafe.StringData(header.TypePath)
+			if first, ok := seen[header.TypePath]; ok && first == data {
+				shared++
+			}
+			seen[header.TypePath] = data
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shared != 0 {
+		t.Error("Interned type paths:", shared)
+	}
+}
+
+func TestWalkRejectsParallelLevels(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "test_creative_v1.1_exp.sav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := Walk(file, VisitorFuncs{}, WithParallelLevels()); err == nil {
+		t.Error("Walk accepted WithParallelLevels")
+	}
+}
@@ -0,0 +1,216 @@
+// Package power groups the buildings of a save into power circuits and reports their capacity.
+package power
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const (
+	circuitType         = "/Script/FactoryGame.FGPowerCircuit"
+	powerConnectionType = "/Script/FactoryGame.FGPowerConnectionComponent"
+	batteryClass        = "Build_PowerStorageMk1_C"
+	wireClassPrefix     = "Build_PowerLine"
+	switchClassInfix    = "PowerSwitch"
+
+	// BatteryCapacity is the storage of one power storage in MWh.
+	BatteryCapacity = 100
+)
+
+// generatorProduction is the power production in MW of generators at 100% clock speed.
+var generatorProduction = map[string]float64{
+	"Build_GeneratorIntegratedBiomass_C": 20,
+	"Build_GeneratorBiomass_Automated_C": 30,
+	"Build_GeneratorCoal_C":              75,
+	"Build_GeneratorFuel_C":              250,
+	"Build_GeneratorNuclear_C":           2500,
+	"Build_GeneratorGeoThermal_C":        200, // average, the output varies with node purity
+	"Build_AlienPowerBuilding_C":         500,
+}
+
+// Circuit is a set of buildings sharing power.
+type Circuit struct {
+	ID int // mCircuitID of the circuit subsystem, -1 for circuits rebuilt from wires
+	// Component is the FGPowerCircuit of the circuit subsystem, nil for circuits rebuilt from wires.
+	Component *saveformat.Component
+	Buildings []*saveformat.Actor // every actor with a connection in the circuit, including poles
+
+	Capacity        float64 // generation capacity at the current clock speeds, MW
+	Consumption     float64 // target consumption at the current clock speeds, MW
+	BatteryStored   float64 // MWh
+	BatteryCapacity float64 // MWh
+	IsFuseTriggered bool
+}
+
+// Circuits returns the power circuits of the save. Circuits are read from the circuit subsystem,
+// connections missing from it are grouped by the wires between them.
+func Circuits(save *saveformat.SaveIndex) ([]*Circuit, error) {
+	var circuits []*Circuit
+	inCircuit := make(map[*saveformat.Component]bool)
+	for _, component := range save.Components {
+		if component.Header.TypePath != circuitType {
+			continue
+		}
+
+		props, err := component.DecodedProperties()
+		if err != nil {
+			return nil, err
+		}
+		circuitID, _ := props.Int("mCircuitID")
+		circuit := &Circuit{ID: int(circuitID), Component: component}
+		circuit.IsFuseTriggered, _ = props.Bool("mIsFuseTriggered")
+
+		refs, _ := props.Array("mComponents")
+		var connections []*saveformat.Component
+		for _, ref := range refs {
+			if connection, ok := save.Component(ref.(saveformat.ObjectReference).PathName); ok {
+				connections = append(connections, connection)
+				inCircuit[connection] = true
+			}
+		}
+		circuit.Buildings = owners(connections)
+		circuits = append(circuits, circuit)
+	}
+
+	rebuilt, err := wireCircuits(save, inCircuit)
+	if err != nil {
+		return nil, err
+	}
+	circuits = append(circuits, rebuilt...)
+
+	for _, circuit := range circuits {
+		if err := circuit.measure(save); err != nil {
+			return nil, err
+		}
+	}
+	return circuits, nil
+}
+
+// wireCircuits groups the connections that are not in a circuit by the wires between them.
+// Connections without a wire, like those of drop pods, are left out.
+func wireCircuits(save *saveformat.SaveIndex, inCircuit map[*saveformat.Component]bool) ([]*Circuit, error) {
+	parent := make(map[*saveformat.Component]*saveformat.Component)
+	var find func(c *saveformat.Component) *saveformat.Component
+	find = func(c *saveformat.Component) *saveformat.Component {
+		if parent[c] != c {
+			parent[c] = find(parent[c])
+		}
+		return parent[c]
+	}
+	union := func(a, b *saveformat.Component) {
+		parent[find(a)] = find(b)
+	}
+
+	var connections []*saveformat.Component
+	wired := make(map[*saveformat.Component]bool)
+	for _, component := range save.Components {
+		if component.Header.TypePath == powerConnectionType && !inCircuit[component] && component.Owner() != nil {
+			parent[component] = component
+			connections = append(connections, component)
+		}
+	}
+	if len(connections) == 0 {
+		return nil, nil
+	}
+
+	for _, actor := range save.Actors {
+		class := model.ClassName(actor.Header.TypePath)
+		switch {
+		case strings.HasPrefix(class, wireClassPrefix):
+			wire, err := model.NewWire(actor)
+			if err != nil {
+				return nil, err
+			}
+			a, aok := save.Component(wire.Connections[0].PathName)
+			b, bok := save.Component(wire.Connections[1].PathName)
+			if aok && bok && parent[a] != nil && parent[b] != nil {
+				union(a, b)
+				wired[a], wired[b] = true, true
+			}
+		case !strings.Contains(class, switchClassInfix):
+			// the connections of one building share a circuit, except for switches
+			var first *saveformat.Component
+			for _, component := range actor.Components {
+				if parent[component] == nil {
+					continue
+				}
+				if first == nil {
+					first = component
+				} else {
+					union(component, first)
+				}
+			}
+		}
+	}
+
+	groups := make(map[*saveformat.Component][]*saveformat.Component)
+	isWired := make(map[*saveformat.Component]bool)
+	var roots []*saveformat.Component
+	for _, connection := range connections {
+		root := find(connection)
+		if groups[root] == nil {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], connection)
+		isWired[root] = isWired[root] || wired[connection]
+	}
+
+	var circuits []*Circuit
+	for _, root := range roots {
+		if isWired[root] {
+			circuits = append(circuits, &Circuit{ID: -1, Buildings: owners(groups[root])})
+		}
+	}
+	return circuits, nil
+}
+
+// owners returns the owning actors of the connections without duplicates, in connection order.
+func owners(connections []*saveformat.Component) []*saveformat.Actor {
+	var actors []*saveformat.Actor
+	for _, connection := range connections {
+		if owner := connection.Owner(); owner != nil && !slices.Contains(actors, owner) {
+			actors = append(actors, owner)
+		}
+	}
+	return actors
+}
+
+// measure sums the capacity, consumption and battery storage of the circuit buildings.
+func (circuit *Circuit) measure(save *saveformat.SaveIndex) error {
+	for _, actor := range circuit.Buildings {
+		building, err := model.NewBuilding(save, actor)
+		if err != nil {
+			return err
+		}
+
+		if building.PowerInfo != nil {
+			circuit.Consumption += float64(building.PowerInfo.TargetConsumption)
+		}
+		if production, ok := generatorProduction[building.Class]; ok {
+			circuit.Capacity += generatorCapacity(building, production)
+		}
+		if building.Class == batteryClass {
+			stored, _ := building.Properties.Float("mPowerStore")
+			circuit.BatteryStored += stored
+			circuit.BatteryCapacity += BatteryCapacity
+		}
+	}
+	return nil
+}
+
+// generatorCapacity prefers the production saved by the generator, which is only present while it
+// runs, over the production of its class scaled by the clock speed.
+func generatorCapacity(building model.Building, production float64) float64 {
+	if info := building.PowerInfo; info != nil && info.BaseProduction+info.DynamicProductionCapacity > 0 {
+		return float64(info.BaseProduction + info.DynamicProductionCapacity)
+	}
+
+	potential, err := building.Properties.Float("mCurrentPotential")
+	if err != nil {
+		potential = 1
+	}
+	return production * potential
+}
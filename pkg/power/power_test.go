@@ -0,0 +1,80 @@
+package power_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/power"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// withoutCircuits removes the circuits of the circuit subsystem, so they are rebuilt from the wires.
+func withoutCircuits(body *saveformat.SaveFileBody) {
+	for l := range body.Levels {
+		level := &body.Levels[l]
+		var headers []saveformat.ComponentHeader
+		var objects []saveformat.ComponentObject
+		for i, header := range level.ComponentHeaders {
+			if header.TypePath != "/Script/FactoryGame.FGPowerCircuit" {
+				headers = append(headers, header)
+				objects = append(objects, level.ComponentObjects[i])
+			}
+		}
+		level.ComponentHeaders, level.ComponentObjects = headers, objects
+	}
+}
+
+func TestCircuits(t *testing.T) {
+	circuits, err := power.Circuits(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(circuits) != 1 {
+		t.Fatal("Circuits:", len(circuits), "want 1")
+	}
+
+	circuit := circuits[0]
+	if circuit.ID != 0 || circuit.Component == nil || circuit.IsFuseTriggered {
+		t.Error("Invalid circuit:", circuit.ID, circuit.Component, circuit.IsFuseTriggered)
+	}
+	classes := make(map[string]int)
+	for _, actor := range circuit.Buildings {
+		classes[model.ClassName(actor.Header.TypePath)]++
+	}
+	if classes["Build_PowerPoleMk1_C"] != 7 || classes["Build_GeneratorIntegratedBiomass_C"] != 2 {
+		t.Error("Circuit buildings:", classes)
+	}
+	if circuit.Capacity != 40 {
+		t.Error("Capacity:", circuit.Capacity, "want 40")
+	}
+	if circuit.Consumption <= 0 || circuit.Consumption > circuit.Capacity {
+		t.Error("Consumption:", circuit.Consumption)
+	}
+
+	// rebuilding the circuit from the wires gives the same buildings
+	body := testsave.LoadBody(t)
+	withoutCircuits(body)
+	rebuilt, err := power.Circuits(saveformat.NewSaveIndex(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rebuilt) != 1 || rebuilt[0].ID != -1 || rebuilt[0].Component != nil {
+		t.Fatal("Rebuilt circuits:", len(rebuilt))
+	}
+	names := func(actors []*saveformat.Actor) []string {
+		var names []string
+		for _, actor := range actors {
+			names = append(names, actor.Header.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+	if got, want := names(rebuilt[0].Buildings), names(circuit.Buildings); !slices.Equal(got, want) {
+		t.Error("Rebuilt circuit buildings:", got, "want", want)
+	}
+	if rebuilt[0].Capacity != circuit.Capacity || rebuilt[0].Consumption != circuit.Consumption {
+		t.Error("Rebuilt capacity:", rebuilt[0].Capacity, rebuilt[0].Consumption)
+	}
+}
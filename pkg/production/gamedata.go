@@ -0,0 +1,82 @@
+package production
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+)
+
+// gamedata.json holds the 1.0 recipes of the production buildings and their items. Regenerate it
+// from the Docs.json of the game after an update.
+//
+//go:generate go run gengamedata.go -docs ${SATISFACTORY_DOCS} -out gamedata.json
+//go:embed gamedata.json
+var gameDataJSON []byte
+
+// Item is an item descriptor, fluid amounts are in m³.
+type Item struct {
+	Class string `json:"class"`
+	Name  string `json:"name"`
+	Fluid bool   `json:"fluid,omitempty"`
+}
+
+type ItemAmount struct {
+	Item   string  `json:"item"`
+	Amount float64 `json:"amount"`
+}
+
+type Recipe struct {
+	Class       string       `json:"class"`
+	Name        string       `json:"name"`
+	Duration    float64      `json:"duration"` // seconds per cycle at 100% clock speed
+	ProducedIn  []string     `json:"producedIn"`
+	Ingredients []ItemAmount `json:"ingredients"`
+	Products    []ItemAmount `json:"products"`
+}
+
+type gameData struct {
+	Items   []*Item   `json:"items"`
+	Recipes []*Recipe `json:"recipes"`
+}
+
+var (
+	items   map[string]*Item
+	recipes map[string]*Recipe
+)
+
+func init() {
+	var data gameData
+	if err := json.Unmarshal(gameDataJSON, &data); err != nil {
+		panic("parsing embedded game data: " + err.Error())
+	}
+
+	items = make(map[string]*Item, len(data.Items))
+	for _, item := range data.Items {
+		items[item.Class] = item
+	}
+	recipes = make(map[string]*Recipe, len(data.Recipes))
+	for _, recipe := range data.Recipes {
+		recipes[recipe.Class] = recipe
+	}
+}
+
+// LookupRecipe returns a recipe by class name or full path, e.g. a building's mCurrentRecipe.
+func LookupRecipe(class string) (*Recipe, bool) {
+	recipe, ok := recipes[model.ClassName(class)]
+	return recipe, ok
+}
+
+// LookupItem returns an item by class name or full path.
+func LookupItem(class string) (*Item, bool) {
+	item, ok := items[model.ClassName(class)]
+	return item, ok
+}
+
+// ItemName returns the display name of an item, or its class name when it is not in the game data.
+func ItemName(class string) string {
+	if item, ok := LookupItem(class); ok {
+		return item.Name
+	}
+	return model.ClassName(class)
+}
@@ -0,0 +1,193 @@
+//go:build ignore
+
+// Gengamedata writes gamedata.json from the Docs.json shipped with the game, in
+// CommunityResources/Docs/. It keeps the recipes made in production buildings and the items they use.
+//
+//	go run gengamedata.go -docs "$SATISFACTORY_DOCS" -out gamedata.json
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// productionBuildings are the buildings whose recipes are kept, the build gun, workbenches and
+// equipment workshop are left out.
+var productionBuildings = []string{
+	"Build_SmelterMk1_C",
+	"Build_FoundryMk1_C",
+	"Build_ConstructorMk1_C",
+	"Build_AssemblerMk1_C",
+	"Build_ManufacturerMk1_C",
+	"Build_OilRefinery_C",
+	"Build_Packager_C",
+	"Build_Blender_C",
+	"Build_HadronCollider_C",
+	"Build_Converter_C",
+	"Build_QuantumEncoder_C",
+}
+
+type item struct {
+	Class string `json:"class"`
+	Name  string `json:"name"`
+	Fluid bool   `json:"fluid,omitempty"`
+}
+
+type itemAmount struct {
+	Item   string  `json:"item"`
+	Amount float64 `json:"amount"`
+}
+
+type recipe struct {
+	Class       string       `json:"class"`
+	Name        string       `json:"name"`
+	Duration    float64      `json:"duration"`
+	ProducedIn  []string     `json:"producedIn"`
+	Ingredients []itemAmount `json:"ingredients"`
+	Products    []itemAmount `json:"products"`
+}
+
+var (
+	classPattern  = regexp.MustCompile(`[A-Za-z0-9_-]+_C\b`)
+	amountPattern = regexp.MustCompile(`ItemClass="?[^,]*?([A-Za-z0-9_-]+_C)'?"?,Amount=([0-9.]+)`)
+)
+
+func main() {
+	docsPath := flag.String("docs", "", "path of Docs.json")
+	outPath := flag.String("out", "gamedata.json", "output file")
+	flag.Parse()
+	if *docsPath == "" {
+		log.Fatal("-docs is required")
+	}
+
+	data, err := os.ReadFile(*docsPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var docs []struct {
+		NativeClass string
+		Classes     []map[string]any
+	}
+	if err := json.Unmarshal(decodeUTF16(data), &docs); err != nil {
+		log.Fatal("parsing ", *docsPath, ": ", err)
+	}
+
+	items := make(map[string]*item)
+	var recipes []*recipe
+	var recipeClasses []map[string]any
+	for _, native := range docs {
+		for _, class := range native.Classes {
+			name, _ := class["ClassName"].(string)
+			switch {
+			case strings.HasSuffix(native.NativeClass, "FGRecipe'"):
+				recipeClasses = append(recipeClasses, class)
+			case class["mForm"] != nil && (strings.HasPrefix(name, "Desc_") || strings.HasPrefix(name, "BP_")):
+				form, _ := class["mForm"].(string)
+				displayName, _ := class["mDisplayName"].(string)
+				items[name] = &item{Class: name, Name: displayName, Fluid: form == "RF_LIQUID" || form == "RF_GAS"}
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, class := range recipeClasses {
+		var producedIn []string
+		for _, building := range classPattern.FindAllString(stringField(class, "mProducedIn"), -1) {
+			if slices.Contains(productionBuildings, building) && !slices.Contains(producedIn, building) {
+				producedIn = append(producedIn, building)
+			}
+		}
+		if len(producedIn) == 0 {
+			continue
+		}
+		duration, _ := strconv.ParseFloat(stringField(class, "mManufactoringDuration"), 64)
+		r := &recipe{
+			Class:       stringField(class, "ClassName"),
+			Name:        stringField(class, "mDisplayName"),
+			Duration:    duration,
+			ProducedIn:  producedIn,
+			Ingredients: amounts(stringField(class, "mIngredients"), items),
+			Products:    amounts(stringField(class, "mProduct"), items),
+		}
+		for _, amount := range append(slices.Clone(r.Ingredients), r.Products...) {
+			used[amount.Item] = true
+		}
+		recipes = append(recipes, r)
+	}
+
+	var kept []*item
+	for class, item := range items {
+		if used[class] {
+			kept = append(kept, item)
+		}
+	}
+	slices.SortFunc(kept, func(a, b *item) int { return strings.Compare(a.Class, b.Class) })
+	slices.SortFunc(recipes, func(a, b *recipe) int { return strings.Compare(a.Class, b.Class) })
+
+	var out bytes.Buffer
+	out.WriteString("{\n\t\"items\": [\n")
+	writeLines(&out, kept)
+	out.WriteString("\t],\n\t\"recipes\": [\n")
+	writeLines(&out, recipes)
+	out.WriteString("\t]\n}\n")
+	if err := os.WriteFile(*outPath, out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(kept), "items,", len(recipes), "recipes")
+}
+
+// decodeUTF16 converts the UTF-16 Docs.json of the game to UTF-8, other input is returned as is.
+func decodeUTF16(data []byte) []byte {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xfe {
+		return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	}
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+func stringField(class map[string]any, name string) string {
+	value, _ := class[name].(string)
+	return value
+}
+
+// amounts parses an item amount list such as
+// ((ItemClass="/Script/Engine.BlueprintGeneratedClass'/Game/.../Desc_IronIngot.Desc_IronIngot_C'",Amount=3)).
+// Fluid amounts are saved in liters and converted to m³.
+func amounts(list string, items map[string]*item) []itemAmount {
+	result := []itemAmount{}
+	for _, match := range amountPattern.FindAllStringSubmatch(list, -1) {
+		amount, _ := strconv.ParseFloat(match[2], 64)
+		if item := items[match[1]]; item != nil && item.Fluid {
+			amount /= 1000
+		}
+		result = append(result, itemAmount{Item: match[1], Amount: amount})
+	}
+	return result
+}
+
+func writeLines[T any](out *bytes.Buffer, values []*T) {
+	for i, value := range values {
+		line, err := json.Marshal(value)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out.WriteString("\t\t")
+		out.Write(line)
+		if i < len(values)-1 {
+			out.WriteByte(',')
+		}
+		out.WriteByte('\n')
+	}
+}
@@ -0,0 +1,93 @@
+// Package production calculates the expected item rates of the production buildings in a save.
+package production
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+)
+
+// BuildingRate is the expected throughput of one building, in items or m³ per minute.
+type BuildingRate struct {
+	Building *model.ProductionBuilding
+	Recipe   *Recipe
+	Consumed []ItemAmount
+	Produced []ItemAmount
+}
+
+// ItemRate is the total throughput of one item, in items or m³ per minute.
+type ItemRate struct {
+	Item     string
+	Produced float64
+	Consumed float64
+}
+
+func (r ItemRate) Net() float64 {
+	return r.Produced - r.Consumed
+}
+
+type Report struct {
+	Buildings []BuildingRate
+	Items     []ItemRate // sorted by item class
+	// UnknownRecipes are the recipes set on buildings that are not in the game data.
+	UnknownRecipes []string
+}
+
+// Rate returns the expected rates of a building running the recipe at its current clock speed
+// and production boost. The clock speed scales both sides, the production boost from
+// somersloops only the products.
+func Rate(building *model.ProductionBuilding, recipe *Recipe) BuildingRate {
+	cyclesPerMinute := 60 / recipe.Duration * float64(building.Potential)
+	boost := float64(building.ProductionBoost)
+
+	rate := BuildingRate{Building: building, Recipe: recipe}
+	for _, ingredient := range recipe.Ingredients {
+		rate.Consumed = append(rate.Consumed, ItemAmount{Item: ingredient.Item, Amount: ingredient.Amount * cyclesPerMinute})
+	}
+	for _, product := range recipe.Products {
+		rate.Produced = append(rate.Produced, ItemAmount{Item: product.Item, Amount: product.Amount * cyclesPerMinute * boost})
+	}
+	return rate
+}
+
+// Calculate sums the expected production and consumption of the buildings per item.
+// Buildings without a recipe are left out.
+func Calculate(buildings []*model.ProductionBuilding) *Report {
+	var report Report
+	totals := make(map[string]*ItemRate)
+	total := func(item string) *ItemRate {
+		if totals[item] == nil {
+			totals[item] = &ItemRate{Item: item}
+		}
+		return totals[item]
+	}
+
+	for _, building := range buildings {
+		if building.Recipe == "" {
+			continue
+		}
+		recipe, ok := LookupRecipe(building.Recipe)
+		if !ok {
+			if !slices.Contains(report.UnknownRecipes, building.Recipe) {
+				report.UnknownRecipes = append(report.UnknownRecipes, building.Recipe)
+			}
+			continue
+		}
+
+		rate := Rate(building, recipe)
+		report.Buildings = append(report.Buildings, rate)
+		for _, consumed := range rate.Consumed {
+			total(consumed.Item).Consumed += consumed.Amount
+		}
+		for _, produced := range rate.Produced {
+			total(produced.Item).Produced += produced.Amount
+		}
+	}
+
+	for _, rate := range totals {
+		report.Items = append(report.Items, *rate)
+	}
+	slices.SortFunc(report.Items, func(a, b ItemRate) int { return strings.Compare(a.Item, b.Item) })
+	return &report
+}
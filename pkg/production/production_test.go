@@ -0,0 +1,118 @@
+package production_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/production"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestCalculate(t *testing.T) {
+	buildings, err := model.NewBuildings(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := production.Calculate(buildings.Production)
+	if len(report.UnknownRecipes) != 0 {
+		t.Error("Unknown recipes:", report.UnknownRecipes)
+	}
+	if len(report.Buildings) != 7 {
+		t.Error("Buildings with a rate:", len(report.Buildings), "want 7")
+	}
+
+	// iron pipe and stitched iron plate, idle with somersloops pending so without a current boost
+	want := map[string][2]float64{
+		"Desc_IronIngot_C":           {0, 100},
+		"Desc_SteelPipe_C":           {25, 0},
+		"Desc_IronPlateReinforced_C": {5.625, 0},
+	}
+	for _, rate := range report.Items {
+		if w, ok := want[rate.Item]; ok && (rate.Produced != w[0] || rate.Consumed != w[1]) {
+			t.Error(production.ItemName(rate.Item), "produced", rate.Produced, "consumed", rate.Consumed, "want", w)
+		}
+		delete(want, rate.Item)
+	}
+	if len(want) != 0 {
+		t.Error("Missing item rates:", want)
+	}
+}
+
+func TestRate(t *testing.T) {
+	recipe, _ := production.LookupRecipe("Recipe_IronPlate_C")
+	building := &model.ProductionBuilding{Potential: 0.5, PendingPotential: 2.5, ProductionBoost: 2, PendingBoost: 1}
+
+	// the current clock speed and boost apply, not the pending ones
+	rate := production.Rate(building, recipe)
+	if rate.Consumed[0].Amount != 15 || rate.Produced[0].Amount != 20 {
+		t.Error("Consumed", rate.Consumed, "produced", rate.Produced)
+	}
+}
+
+func TestGameData(t *testing.T) {
+	recipe, ok := production.LookupRecipe("/Game/FactoryGame/Recipes/Constructor/Recipe_IronPlate.Recipe_IronPlate_C")
+	if !ok || recipe.Name != "Iron Plate" {
+		t.Fatal("Iron plate recipe not found")
+	}
+	if name := production.ItemName("Desc_Unknown_C"); name != "Desc_Unknown_C" {
+		t.Error("Name of unknown item:", name)
+	}
+	if item, ok := production.LookupItem("Desc_NitrogenGas_C"); !ok || !item.Fluid {
+		t.Error("Nitrogen gas is not a fluid")
+	}
+}
+
+// machineRecipes are the folders of the recipes made in production buildings, the others are
+// built with the build gun or crafted in the equipment workshop.
+var machineRecipes = []string{
+	"/AlternateRecipes/", "/Smelter/", "/Constructor/", "/Assembler/", "/Manufacturer/", "/OilRefinery/",
+	"/Packager/", "/Blender/", "/HadronCollider/", "/Converter/", "/QuantumEncoder/", "/SpaceElevatorParts/",
+}
+
+func TestGameDataCoversSave(t *testing.T) {
+	// the creative test save has every recipe unlocked
+	var available []saveformat.ObjectReference
+	for _, actor := range testsave.Load(t).Actors {
+		if actor.Header.TypePath == "/Script/FactoryGame.FGRecipeManager" {
+			props, err := actor.DecodedProperties()
+			if err != nil {
+				t.Fatal(err)
+			}
+			elements, _ := props.Array("mAvailableRecipes")
+			for _, element := range elements {
+				available = append(available, element.(saveformat.ObjectReference))
+			}
+		}
+	}
+
+	var checked, alternates int
+	for _, ref := range available {
+		if !slices.ContainsFunc(machineRecipes, func(folder string) bool { return strings.Contains(ref.PathName, folder) }) {
+			continue
+		}
+		checked++
+		recipe, ok := production.LookupRecipe(ref.PathName)
+		if !ok {
+			t.Error("Recipe not in the game data:", ref.PathName)
+			continue
+		}
+		if strings.HasPrefix(recipe.Name, "Alternate: ") {
+			alternates++
+		}
+		if recipe.Duration <= 0 || len(recipe.ProducedIn) == 0 || len(recipe.Products) == 0 {
+			t.Error("Invalid recipe:", recipe.Class)
+		}
+		for _, amount := range append(slices.Clone(recipe.Ingredients), recipe.Products...) {
+			if _, ok := production.LookupItem(amount.Item); !ok || amount.Amount <= 0 {
+				t.Error("Invalid item of", recipe.Class, amount)
+			}
+		}
+	}
+	if checked < 250 || alternates < 100 {
+		t.Error("Recipes checked:", checked, "alternates:", alternates)
+	}
+}
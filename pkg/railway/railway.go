@@ -0,0 +1,292 @@
+// Package railway extracts the rail network, stations and train timetables of a save.
+package railway
+
+import (
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const trackConnectionType = "/Script/FactoryGame.FGRailroadTrackConnectionComponent"
+
+// Segment is a piece of railroad track, including the tracks built into stations and platforms.
+type Segment struct {
+	Actor             *saveformat.Actor
+	Class             string
+	Length            float64 // cm, along the spline
+	IsOwnedByPlatform bool
+	Ends              []*TrackEnd
+}
+
+// TrackEnd is a track connection at the end of a segment. Ends with more than one
+// connected end are switches.
+type TrackEnd struct {
+	Component      *saveformat.Component
+	Segment        *Segment
+	Connected      []*TrackEnd
+	SwitchPosition int
+}
+
+// Neighbours returns the segments connected to the ends of the segment.
+func (segment *Segment) Neighbours() []*Segment {
+	var segments []*Segment
+	for _, end := range segment.Ends {
+		for _, connected := range end.Connected {
+			segments = append(segments, connected.Segment)
+		}
+	}
+	return segments
+}
+
+// DockingRule is what a train does at a stop.
+type DockingRule struct {
+	Definition        string   // LoadUnloadOnce or FullyLoadUnload
+	Duration          float64  // seconds to stay docked
+	IsDurationAndRule bool     // wait for the duration and the definition, instead of either
+	LoadFilter        []string // item classes to load, all items when empty
+	UnloadFilter      []string // item classes to unload, all items when empty
+}
+
+type Stop struct {
+	Identifier *saveformat.Actor
+	Station    *model.TrainStation // nil when the station no longer exists
+	Rule       DockingRule
+}
+
+type Train struct {
+	*model.Train
+	Stops       []Stop
+	CurrentStop int
+}
+
+type Network struct {
+	Segments []*Segment
+	Stations []*model.TrainStation
+	Trains   []*Train
+
+	segments map[*saveformat.Actor]*Segment
+}
+
+// NewNetwork reads the tracks, stations and trains of the save.
+func NewNetwork(save *saveformat.SaveIndex) (*Network, error) {
+	network := &Network{segments: make(map[*saveformat.Actor]*Segment)}
+	ends := make(map[*saveformat.Component]*TrackEnd)
+	identifiers := make(map[*saveformat.Actor]*model.TrainStation)
+
+	var trains []*saveformat.Actor
+	for _, actor := range save.Actors {
+		switch {
+		case model.ClassName(actor.Header.TypePath) == model.TrainClass:
+			trains = append(trains, actor)
+		case model.FamilyOf(actor.Header.TypePath) == model.FamilyTrainStation:
+			station, err := model.NewTrainStation(save, actor)
+			if err != nil {
+				return nil, err
+			}
+			network.Stations = append(network.Stations, station)
+			if station.Identifier != nil {
+				identifiers[station.Identifier] = station
+			}
+		}
+
+		segment, err := newSegment(actor)
+		if err != nil {
+			return nil, err
+		}
+		if segment != nil {
+			network.Segments = append(network.Segments, segment)
+			network.segments[actor] = segment
+			for _, end := range segment.Ends {
+				ends[end.Component] = end
+			}
+		}
+	}
+
+	for _, segment := range network.Segments {
+		for _, end := range segment.Ends {
+			props, err := end.Component.DecodedProperties()
+			if err != nil {
+				return nil, err
+			}
+			end.SwitchPosition = int(orZero(props.Int("mSwitchPosition")))
+			refs, _ := props.Array("mConnectedComponents")
+			for _, ref := range refs {
+				ref, _ := ref.(saveformat.ObjectReference)
+				if component, ok := save.Component(ref.PathName); ok && ends[component] != nil {
+					end.Connected = append(end.Connected, ends[component])
+				}
+			}
+		}
+	}
+
+	for _, actor := range trains {
+		train, err := newTrain(save, actor, identifiers)
+		if err != nil {
+			return nil, err
+		}
+		network.Trains = append(network.Trains, train)
+	}
+	return network, nil
+}
+
+// Segment returns the segment of a track actor, nil when the actor is not a track.
+func (network *Network) Segment(actor *saveformat.Actor) *Segment {
+	return network.segments[actor]
+}
+
+func newSegment(actor *saveformat.Actor) (*Segment, error) {
+	var segment *Segment
+	for _, component := range actor.Components {
+		if component.Header.TypePath != trackConnectionType {
+			continue
+		}
+		if segment == nil {
+			segment = &Segment{Actor: actor, Class: model.ClassName(actor.Header.TypePath)}
+		}
+		segment.Ends = append(segment.Ends, &TrackEnd{Component: component, Segment: segment})
+	}
+	if segment == nil {
+		return nil, nil
+	}
+
+	props, err := actor.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+	segment.IsOwnedByPlatform, _ = props.Bool("mIsOwnedByPlatform")
+
+	points, _ := props.Array("mSplineData")
+	var previous *splinePoint
+	for _, element := range points {
+		fields, ok := element.([]saveformat.Property)
+		if !ok {
+			continue
+		}
+		point, ok := newSplinePoint(fields)
+		if !ok {
+			continue
+		}
+		if previous != nil {
+			segment.Length += curveLength(*previous, point)
+		}
+		previous = &point
+	}
+	return segment, nil
+}
+
+// splinePoint is a point of a track spline, relative to the track actor.
+type splinePoint struct {
+	location, arriveTangent, leaveTangent geometry.Vector
+}
+
+// newSplinePoint reads a spline point, tangents that are not saved are zero.
+func newSplinePoint(fields saveformat.Properties) (splinePoint, bool) {
+	vector := func(name string) (geometry.Vector, bool) {
+		prop, err := fields.Get(name)
+		if err != nil {
+			return geometry.Vector{}, false
+		}
+		v, ok := prop.Value.(readfields.Vector)
+		return geometry.Vector(v), ok
+	}
+	var point splinePoint
+	var ok bool
+	if point.location, ok = vector("Location"); !ok {
+		return point, false
+	}
+	point.arriveTangent, _ = vector("ArriveTangent")
+	point.leaveTangent, _ = vector("LeaveTangent")
+	return point, true
+}
+
+// gaussLegendre are the nodes on [0, 1] and weights of 5 point Gauss-Legendre quadrature.
+var gaussLegendre = [5][2]float64{
+	{0.5 - 0.9061798459386640/2, 0.2369268850561891 / 2},
+	{0.5 - 0.5384693101056831/2, 0.4786286704993665 / 2},
+	{0.5, 0.5688888888888889 / 2},
+	{0.5 + 0.5384693101056831/2, 0.4786286704993665 / 2},
+	{0.5 + 0.9061798459386640/2, 0.2369268850561891 / 2},
+}
+
+// curveLength integrates the speed along the cubic Hermite curve between two spline points, the
+// curve Unreal's spline components evaluate.
+func curveLength(from, to splinePoint) float64 {
+	p0, t0, p1, t1 := from.location, from.leaveTangent, to.location, to.arriveTangent
+	var length float64
+	for _, node := range gaussLegendre {
+		t := node[0]
+		derivative := p0.Scale(6*t*t - 6*t).
+			Add(t0.Scale(3*t*t - 4*t + 1)).
+			Add(p1.Scale(6*t - 6*t*t)).
+			Add(t1.Scale(3*t*t - 2*t))
+		length += node[1] * derivative.Length()
+	}
+	return length
+}
+
+func newTrain(save *saveformat.SaveIndex, actor *saveformat.Actor, identifiers map[*saveformat.Actor]*model.TrainStation) (*Train, error) {
+	base, err := model.NewTrain(save, actor)
+	if err != nil {
+		return nil, err
+	}
+	train := &Train{Train: base}
+	if base.TimeTable == nil {
+		return train, nil
+	}
+
+	props, err := base.TimeTable.DecodedProperties()
+	if err != nil {
+		return nil, err
+	}
+	train.CurrentStop = int(orZero(props.Int("mCurrentStop")))
+
+	stops, _ := props.Array("mStops")
+	for _, element := range stops {
+		fields, ok := element.([]saveformat.Property)
+		if !ok {
+			continue
+		}
+		stop := saveformat.Properties(fields)
+
+		var s Stop
+		if ref, err := stop.Object("Station"); err == nil {
+			s.Identifier, _ = save.Actor(ref.PathName)
+			s.Station = identifiers[s.Identifier]
+		}
+		rule, _ := stop.Struct("DockingRuleSet")
+		s.Rule = dockingRule(rule)
+		train.Stops = append(train.Stops, s)
+	}
+	return train, nil
+}
+
+func dockingRule(props saveformat.Properties) DockingRule {
+	rule := DockingRule{Definition: "LoadUnloadOnce"}
+	if definition, err := props.Str("DockingDefinition"); err == nil {
+		rule.Definition = strings.TrimPrefix(definition[strings.LastIndex(definition, ":")+1:], "TDD_")
+	}
+	rule.Duration = orZero(props.Float("DockForDuration"))
+	rule.IsDurationAndRule = orZero(props.Bool("IsDurationAndRule"))
+	rule.LoadFilter = itemClasses(props, "LoadFilterDescriptors")
+	rule.UnloadFilter = itemClasses(props, "UnloadFilterDescriptors")
+	return rule
+}
+
+func itemClasses(props saveformat.Properties, name string) []string {
+	refs, _ := props.Array(name)
+	var classes []string
+	for _, ref := range refs {
+		if ref, ok := ref.(saveformat.ObjectReference); ok {
+			classes = append(classes, ref.PathName)
+		}
+	}
+	return classes
+}
+
+// orZero returns the value of an accessor, or the zero value when the property is not saved.
+func orZero[T any](value T, _ error) T {
+	return value
+}
@@ -0,0 +1,178 @@
+package railway_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/readsave/readfields"
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/railway"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestNetworkTestSave(t *testing.T) {
+	network, err := railway.NewNetwork(testsave.Load(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the test save has no railway
+	if len(network.Segments) != 0 || len(network.Stations) != 0 || len(network.Trains) != 0 {
+		t.Error("Segments:", len(network.Segments), "stations:", len(network.Stations), "trains:", len(network.Trains))
+	}
+}
+
+// text encodes a culture invariant text property value.
+func text(s string) []byte {
+	data := []byte{0, 0, 0, 0, 0xff, 1, 0, 0, 0}
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s)+1))
+	return append(append(data, s...), 0)
+}
+
+var ref = testsave.Ref
+
+// testNetwork builds two connected tracks, a station and a train stopping at it.
+func testNetwork() *saveformat.SaveIndex {
+	var level testsave.Level
+	addTrackEnd := func(name string, parent string, connectedTo ...string) {
+		var refs []saveformat.ObjectReference
+		for _, pathName := range connectedTo {
+			refs = append(refs, ref(pathName))
+		}
+		level.AddComponent(name, "/Script/FactoryGame.FGRailroadTrackConnectionComponent", parent,
+			saveformat.Property{Name: "mConnectedComponents", Type: "ArrayProperty", InnerType: "ObjectProperty", Value: refs},
+		)
+	}
+	splinePoint := func(x float64) []saveformat.Property {
+		return []saveformat.Property{{Name: "Location", Type: "StructProperty", InnerType: "Vector", Value: readfields.Vector{X: x}}}
+	}
+
+	level.AddActor("TrackA", "Build_RailroadTrack.Build_RailroadTrack_C",
+		saveformat.Property{Name: "mSplineData", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{Value: []any{splinePoint(0), splinePoint(1200), splinePoint(3000)}}},
+	)
+	level.AddActor("TrackB", "Build_RailroadTrackIntegrated.Build_RailroadTrackIntegrated_C",
+		saveformat.Property{Name: "mIsOwnedByPlatform", Type: "BoolProperty", Value: byte(1)},
+	)
+	addTrackEnd("TrackA.TrackConnection0", "TrackA")
+	addTrackEnd("TrackA.TrackConnection1", "TrackA", "TrackB.TrackConnection0")
+	addTrackEnd("TrackB.TrackConnection0", "TrackB", "TrackA.TrackConnection1")
+	addTrackEnd("TrackB.TrackConnection1", "TrackB")
+
+	level.AddActor("Station", "Build_TrainStation.Build_TrainStation_C")
+	level.AddActor("StationIdentifier", "FGTrainStationIdentifier",
+		saveformat.Property{Name: "mStation", Type: "ObjectProperty", Value: ref("Station")},
+		saveformat.Property{Name: "mStationName", Type: "TextProperty", Raw: text("Iron Mine")},
+	).TypePath = "/Script/FactoryGame.FGTrainStationIdentifier"
+
+	level.AddActor("Train", "BP_Train.BP_Train_C",
+		saveformat.Property{Name: "mTrainName", Type: "TextProperty", Raw: text("Ore Express")},
+		saveformat.Property{Name: "TimeTable", Type: "ObjectProperty", Value: ref("TimeTable")},
+		saveformat.Property{Name: "mIsSelfDrivingEnabled", Type: "BoolProperty", Value: byte(1)},
+	)
+	level.AddActor("TimeTable", "FGRailroadTimeTable",
+		saveformat.Property{Name: "mStops", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{Value: []any{
+			[]saveformat.Property{
+				{Name: "Station", Type: "ObjectProperty", Value: ref("StationIdentifier")},
+				{Name: "DockingRuleSet", Type: "StructProperty", InnerType: "TrainDockingRuleSet", Value: []saveformat.Property{
+					{Name: "DockingDefinition", Type: "EnumProperty", Value: "ETrainDockingDefinition::TDD_FullyLoadUnload"},
+					{Name: "DockForDuration", Type: "FloatProperty", Value: float32(30)},
+					{Name: "LoadFilterDescriptors", Type: "ArrayProperty", InnerType: "ObjectProperty", Value: []saveformat.ObjectReference{ref("/Game/Desc_OreIron.Desc_OreIron_C")}},
+				}},
+			},
+			[]saveformat.Property{
+				{Name: "Station", Type: "ObjectProperty", Value: ref("RemovedIdentifier")},
+			},
+		}}},
+		saveformat.Property{Name: "mCurrentStop", Type: "IntProperty", Value: int32(1)},
+	)
+
+	return level.Index()
+}
+
+func TestNetwork(t *testing.T) {
+	save := testNetwork()
+	network, err := railway.NewNetwork(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(network.Segments) != 2 {
+		t.Fatal("Segments:", len(network.Segments))
+	}
+	a, b := network.Segments[0], network.Segments[1]
+	if math.Abs(a.Length-3000) > 1e-6 || a.IsOwnedByPlatform || !b.IsOwnedByPlatform {
+		t.Error("Invalid segments:", a.Length, a.IsOwnedByPlatform, b.IsOwnedByPlatform)
+	}
+	if n := a.Neighbours(); len(n) != 1 || n[0] != b {
+		t.Error("Neighbours of track A:", len(n))
+	}
+	if len(a.Ends[0].Connected) != 0 || b.Ends[1].Connected != nil {
+		t.Error("Open track ends are connected")
+	}
+	if actor, _ := save.Actor("TrackB"); network.Segment(actor) != b {
+		t.Error("Segment lookup failed")
+	}
+
+	if len(network.Stations) != 1 || network.Stations[0].Name != "Iron Mine" {
+		t.Fatal("Stations:", len(network.Stations))
+	}
+
+	if len(network.Trains) != 1 {
+		t.Fatal("Trains:", len(network.Trains))
+	}
+	train := network.Trains[0]
+	if train.Name != "Ore Express" || !train.IsSelfDriving || train.CurrentStop != 1 || len(train.Stops) != 2 {
+		t.Fatal("Invalid train:", train.Name, train.IsSelfDriving, train.CurrentStop, len(train.Stops))
+	}
+	stop := train.Stops[0]
+	if stop.Station != network.Stations[0] || stop.Rule.Definition != "FullyLoadUnload" || stop.Rule.Duration != 30 {
+		t.Error("Invalid stop:", stop.Rule)
+	}
+	if len(stop.Rule.LoadFilter) != 1 || stop.Rule.UnloadFilter != nil {
+		t.Error("Invalid filters:", stop.Rule.LoadFilter, stop.Rule.UnloadFilter)
+	}
+	if removed := train.Stops[1]; removed.Station != nil || removed.Rule.Definition != "LoadUnloadOnce" {
+		t.Error("Invalid stop at removed station:", removed.Rule)
+	}
+}
+
+func TestSegmentLengthFollowsTangents(t *testing.T) {
+	// a quarter circle of 10000 cm radius, tangents of 1.6569 radius fit a circle within 0.03%
+	const radius = 10000.0
+	splinePoint := func(location, tangent readfields.Vector) []saveformat.Property {
+		return []saveformat.Property{
+			{Name: "Location", Type: "StructProperty", InnerType: "Vector", Value: location},
+			{Name: "ArriveTangent", Type: "StructProperty", InnerType: "Vector", Value: tangent},
+			{Name: "LeaveTangent", Type: "StructProperty", InnerType: "Vector", Value: tangent},
+		}
+	}
+	var level testsave.Level
+	level.AddActor("Track", "Build_RailroadTrack.Build_RailroadTrack_C",
+		saveformat.Property{Name: "mSplineData", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{Value: []any{
+			splinePoint(readfields.Vector{}, readfields.Vector{X: 1.6569 * radius}),
+			splinePoint(readfields.Vector{X: radius, Y: radius}, readfields.Vector{Y: 1.6569 * radius}),
+		}}},
+	)
+	level.AddComponent("Track.TrackConnection0", "/Script/FactoryGame.FGRailroadTrackConnectionComponent", "Track")
+
+	network, err := railway.NewNetwork(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(network.Segments) != 1 {
+		t.Fatal("Segments:", len(network.Segments))
+	}
+	if length, want := network.Segments[0].Length, math.Pi/2*radius; math.Abs(length-want) > want*0.001 {
+		t.Error("Length:", length, "want", want)
+	}
+}
+
+func TestNetworkUnknownTextHistory(t *testing.T) {
+	var level testsave.Level
+	level.AddActor("Train", "BP_Train.BP_Train_C",
+		saveformat.Property{Name: "mTrainName", Type: "TextProperty", Raw: []byte{0, 0, 0, 0, 5}},
+	)
+	if _, err := railway.NewNetwork(level.Index()); err == nil {
+		t.Error("Unknown text history type is not an error")
+	}
+}
@@ -0,0 +1,123 @@
+//go:build ignore
+
+// Gennodes writes nodes.json from JSON exports of the map levels, as written by FModel for
+// /Game/FactoryGame/Map/GameLevel01/Persistent_Level and its world partition cells. Arguments
+// are export files or directories searched for .json files.
+//
+//	go run gennodes.go -out nodes.json "$SATISFACTORY_MAP_EXPORT"
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var nodeClasses = []string{"BP_ResourceNode_C", "BP_ResourceNodeGeyser_C", "BP_FrackingCore_C", "BP_FrackingSatellite_C"}
+
+// purities maps EResourcePurity values to the names read by resources.Purity, nodes without a
+// saved purity have the default RP_Normal.
+var purities = map[string]string{
+	"EResourcePurity::RP_Inpure": "impure", // sic
+	"EResourcePurity::RP_Impure": "impure",
+	"EResourcePurity::RP_Normal": "normal",
+	"EResourcePurity::RP_Pure":   "pure",
+}
+
+var classPattern = regexp.MustCompile(`[A-Za-z0-9_-]+_C\b`)
+
+type export struct {
+	Type       string
+	Name       string
+	Properties struct {
+		ResourceClass *struct{ ObjectName string } `json:"mResourceClass"`
+		Purity        string                       `json:"mPurity"`
+	}
+}
+
+type nodeInfo struct {
+	Resource string `json:"resource,omitempty"`
+	Purity   string `json:"purity"`
+}
+
+func main() {
+	outPath := flag.String("out", "nodes.json", "output file")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		log.Fatal("no map exports given")
+	}
+
+	nodes := make(map[string]nodeInfo)
+	for _, root := range flag.Args() {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".json") {
+				return err
+			}
+			return readExports(path, nodes)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var out bytes.Buffer
+	out.WriteString("{\n")
+	for i, name := range names {
+		key, _ := json.Marshal(name)
+		value, _ := json.Marshal(nodes[name])
+		fmt.Fprintf(&out, "\t%s: %s", key, value)
+		if i < len(names)-1 {
+			out.WriteByte(',')
+		}
+		out.WriteByte('\n')
+	}
+	out.WriteString("}\n")
+	if err := os.WriteFile(*outPath, out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(nodes), "nodes")
+}
+
+func readExports(path string, nodes map[string]nodeInfo) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var exports []export
+	if err := json.Unmarshal(data, &exports); err != nil {
+		// not a level export
+		return nil
+	}
+	for _, e := range exports {
+		if !slices.Contains(nodeClasses, e.Type) {
+			continue
+		}
+		info := nodeInfo{Purity: "normal"}
+		if e.Properties.Purity != "" {
+			purity, ok := purities[e.Properties.Purity]
+			if !ok {
+				return fmt.Errorf("%s: %s has unknown purity %s", path, e.Name, e.Properties.Purity)
+			}
+			info.Purity = purity
+		}
+		if e.Properties.ResourceClass != nil {
+			info.Resource = classPattern.FindString(e.Properties.ResourceClass.ObjectName)
+		}
+		nodes[e.Name] = info
+	}
+	return nil
+}
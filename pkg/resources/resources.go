@@ -0,0 +1,207 @@
+// Package resources reports the resource nodes of the map and the extractors occupying them.
+package resources
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+type Purity int
+
+const (
+	PurityUnknown Purity = iota
+	PurityImpure
+	PurityNormal
+	PurityPure
+)
+
+var purityNames = [...]string{"unknown", "impure", "normal", "pure"}
+
+func (p Purity) String() string {
+	if p < 0 || int(p) >= len(purityNames) {
+		return purityNames[PurityUnknown]
+	}
+	return purityNames[p]
+}
+
+func (p *Purity) UnmarshalText(text []byte) error {
+	for i, name := range purityNames {
+		if strings.EqualFold(string(text), name) {
+			*p = Purity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid purity %q", text)
+}
+
+type NodeKind int
+
+const (
+	KindNode          NodeKind = iota // ore and oil nodes
+	KindGeyser                        // geothermal nodes
+	KindWellCore                      // resource well center, occupied by a pressurizer
+	KindWellSatellite                 // resource well node, occupied by a well extractor
+)
+
+var nodeKinds = map[string]NodeKind{
+	"BP_ResourceNode_C":       KindNode,
+	"BP_ResourceNodeGeyser_C": KindGeyser,
+	"BP_FrackingCore_C":       KindWellCore,
+	"BP_FrackingSatellite_C":  KindWellSatellite,
+}
+
+// NodeInfo is the resource and purity of a node, which are part of the map and not saved.
+type NodeInfo struct {
+	Resource string `json:"resource"` // item class, e.g. Desc_OreIron_C
+	Purity   Purity `json:"purity"`
+}
+
+// NodeTable maps node names, e.g. BP_ResourceNode620, to their resource and purity.
+type NodeTable map[string]NodeInfo
+
+// nodes.json is the node table of the world, written by gennodes.go from an export of the map actors.
+//
+//go:generate go run gennodes.go -out nodes.json ${SATISFACTORY_MAP_EXPORT}
+//go:embed nodes.json
+var nodesJSON []byte
+
+// known is the embedded node table.
+var known NodeTable
+
+func init() {
+	var err error
+	if known, err = ReadNodeTable(bytes.NewReader(nodesJSON)); err != nil {
+		panic("parsing embedded node table: " + err.Error())
+	}
+}
+
+// ReadNodeTable reads a node table from a JSON object of node names to node infos.
+func ReadNodeTable(r io.Reader) (NodeTable, error) {
+	var table NodeTable
+	if err := json.NewDecoder(r).Decode(&table); err != nil {
+		return nil, fmt.Errorf("reading node table: %w", err)
+	}
+	return table, nil
+}
+
+// lookup returns the node info from the override table, or else from the embedded table.
+func (table NodeTable) lookup(name string) (NodeInfo, bool) {
+	if info, ok := table[name]; ok {
+		return info, true
+	}
+	info, ok := known[name]
+	return info, ok
+}
+
+// Occupant is the extractor, or geothermal generator, built on a node.
+type Occupant struct {
+	model.Building
+	Tier      int
+	Potential float32 // clock speed, 1 is 100%
+}
+
+type Node struct {
+	Actor    *saveformat.Actor
+	Kind     NodeKind
+	Resource string // item class, empty when it is not in the node table and no extractor has mined it
+	Purity   Purity
+	X, Y, Z  float32
+	Occupant *Occupant // nil when the node is free
+}
+
+func (node *Node) IsOccupied() bool {
+	return node.Occupant != nil
+}
+
+// Nodes returns all resource nodes of the save with their occupants. Resources and purities are
+// taken from the embedded node table, entries of the override table take precedence and it may be
+// nil. Without a table entry, the resource of an occupied node is taken from the output inventory
+// of its extractor.
+func Nodes(save *saveformat.SaveIndex, override NodeTable) ([]*Node, error) {
+	var nodes []*Node
+	byActor := make(map[*saveformat.Actor]*Node)
+	var occupants []*saveformat.Actor
+	for _, actor := range save.Actors {
+		class := model.ClassName(actor.Header.TypePath)
+		if family := model.FamilyOf(actor.Header.TypePath); family == model.FamilyExtractor || class == "Build_GeneratorGeoThermal_C" {
+			occupants = append(occupants, actor)
+		}
+
+		kind, ok := nodeKinds[class]
+		if !ok {
+			continue
+		}
+		node := &Node{
+			Actor: actor,
+			Kind:  kind,
+			X:     actor.Header.PositionX,
+			Y:     actor.Header.PositionY,
+			Z:     actor.Header.PositionZ,
+		}
+		if info, ok := override.lookup(nodeName(actor)); ok {
+			node.Resource, node.Purity = info.Resource, info.Purity
+		}
+		nodes = append(nodes, node)
+		byActor[actor] = node
+	}
+
+	for _, actor := range occupants {
+		occupant, resource, err := newOccupant(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		ref, _ := occupant.Properties.Object("mExtractableResource")
+		nodeActor, _ := save.Actor(ref.PathName)
+		if node := byActor[nodeActor]; node != nil {
+			node.Occupant = occupant
+			if node.Resource == "" {
+				node.Resource = resource
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// newOccupant returns the occupant and the item class in its output inventory, if any.
+func newOccupant(save *saveformat.SaveIndex, actor *saveformat.Actor) (*Occupant, string, error) {
+	if model.FamilyOf(actor.Header.TypePath) != model.FamilyExtractor {
+		generator, err := model.NewGenerator(save, actor)
+		if err != nil {
+			return nil, "", err
+		}
+		return &Occupant{Building: generator.Building, Tier: 1, Potential: generator.Potential}, "", nil
+	}
+
+	extractor, err := model.NewExtractor(save, actor)
+	if err != nil {
+		return nil, "", err
+	}
+	occupant := &Occupant{Building: extractor.Building, Tier: extractor.Tier, Potential: extractor.Potential}
+	if extractor.OutputInventory != nil && len(extractor.OutputInventory.Stacks) > 0 {
+		return occupant, model.ClassName(extractor.OutputInventory.Stacks[0].ItemClass), nil
+	}
+	return occupant, "", nil
+}
+
+// nodeName returns the actor name without its level, the key of the node table.
+func nodeName(actor *saveformat.Actor) string {
+	return actor.Header.Name[strings.LastIndex(actor.Header.Name, ".")+1:]
+}
+
+// Unoccupied returns the free nodes of at least the given purity.
+func Unoccupied(nodes []*Node, purity Purity) []*Node {
+	var free []*Node
+	for _, node := range nodes {
+		if !node.IsOccupied() && node.Purity >= purity {
+			free = append(free, node)
+		}
+	}
+	return free
+}
@@ -0,0 +1,87 @@
+package resources_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/resources"
+)
+
+func TestNodes(t *testing.T) {
+	table, err := resources.ReadNodeTable(strings.NewReader(`{
+		"BP_ResourceNode557": {"resource": "Desc_OreCopper_C", "purity": "pure"},
+		"BP_ResourceNode620": {"resource": "Desc_Stone_C", "purity": "Normal"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := resources.Nodes(testsave.Load(t), table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 459+31+17+118 {
+		t.Error("Nodes:", len(nodes))
+	}
+
+	found := make(map[string]*resources.Node)
+	occupied := 0
+	for _, node := range nodes {
+		found[node.Actor.Header.Name[strings.LastIndex(node.Actor.Header.Name, ".")+1:]] = node
+		if node.IsOccupied() {
+			occupied++
+		}
+	}
+	if occupied != 2 {
+		t.Error("Occupied nodes:", occupied, "want 2")
+	}
+
+	if node := found["BP_ResourceNode557"]; node == nil || node.Occupant == nil || node.Occupant.Tier != 3 || node.Resource != "Desc_OreCopper_C" || node.Purity != resources.PurityPure {
+		t.Error("Invalid node with table entry:", node)
+	}
+	if node := found["BP_ResourceNode551"]; node == nil || node.Occupant == nil || node.Occupant.Tier != 2 || node.Resource != "Desc_OreIron_C" || node.Purity != resources.PurityUnknown {
+		t.Error("Invalid node with resource from its extractor:", node)
+	}
+	if node := found["BP_ResourceNode620"]; node == nil || node.X == 0 || node.Purity.String() != "normal" {
+		t.Error("Invalid free node:", node)
+	}
+
+	if free := resources.Unoccupied(nodes, resources.PurityNormal); len(free) != 1 || free[0] != found["BP_ResourceNode620"] {
+		t.Error("Unoccupied nodes of normal purity:", len(free))
+	}
+	if free := resources.Unoccupied(nodes, resources.PurityUnknown); len(free) != len(nodes)-2 {
+		t.Error("Unoccupied nodes:", len(free))
+	}
+}
+
+func TestEmbeddedNodeTable(t *testing.T) {
+	nodes, err := resources.Nodes(testsave.Load(t), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the extractors of the test save mine these nodes, the table must agree with them
+	missing := 0
+	for _, node := range nodes {
+		name := node.Actor.Header.Name[strings.LastIndex(node.Actor.Header.Name, ".")+1:]
+		if want, ok := map[string]string{"BP_ResourceNode551": "Desc_OreIron_C", "BP_ResourceNode557": "Desc_Stone_C"}[name]; ok && node.Resource != want {
+			t.Error("Resource of", name, "is", node.Resource, "want", want)
+		}
+		if node.Resource == "" || node.Purity == resources.PurityUnknown {
+			missing++
+		}
+	}
+	if missing == len(nodes) {
+		t.Skip("nodes.json is empty, generate it from a map export")
+	}
+	if missing != 0 {
+		t.Error("Nodes missing from the embedded table:", missing)
+	}
+}
+
+func TestReadNodeTable(t *testing.T) {
+	if _, err := resources.ReadNodeTable(strings.NewReader(`{"BP_ResourceNode1": {"purity": "rich"}}`)); err == nil {
+		t.Error("Invalid purity was accepted")
+	}
+}
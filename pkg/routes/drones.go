@@ -0,0 +1,74 @@
+// Package routes extracts the drone port pairings and the recorded paths of self-driving vehicles
+// with the truck stations along them.
+package routes
+
+import (
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+const dronePortClass = "Build_DroneStation_C"
+
+// DronePort is a drone port with its drone and the port it is paired with.
+type DronePort struct {
+	model.Building
+	Info          *saveformat.Actor // FGDroneStationInfo of the port
+	Paired        *DronePort        // nil when the port is not paired
+	Drone         *saveformat.Actor // nil when no drone is docked to the port
+	FuelInventory *model.Inventory
+	Fuel          int    // items in the fuel inventory, e.g. batteries
+	Status        string // drone status without the enum prefix, e.g. EDS_EN_ROUTE, empty when not saved
+}
+
+// DronePorts returns the drone ports of the save with their pairings resolved.
+func DronePorts(save *saveformat.SaveIndex) ([]*DronePort, error) {
+	var ports []*DronePort
+	byInfo := make(map[*saveformat.Actor]*DronePort)
+	paired := make(map[*DronePort]string)
+
+	for _, actor := range save.Actors {
+		if model.ClassName(actor.Header.TypePath) != dronePortClass {
+			continue
+		}
+		building, err := model.NewBuilding(save, actor)
+		if err != nil {
+			return nil, err
+		}
+
+		port := &DronePort{Building: building}
+		port.Info = model.ActorProperty(save, building.Properties, "mInfo")
+		port.Drone = model.ActorProperty(save, building.Properties, "mStationDrone")
+		if port.FuelInventory, err = model.InventoryProperty(save, building.Properties, "mBatteryInventory"); err != nil {
+			return nil, err
+		}
+		if port.FuelInventory != nil {
+			for _, stack := range port.FuelInventory.Stacks {
+				port.Fuel += stack.Count
+			}
+		}
+
+		if port.Info != nil {
+			props, err := port.Info.DecodedProperties()
+			if err != nil {
+				return nil, err
+			}
+			if status, err := props.Str("mDroneStatus"); err == nil {
+				port.Status = status[strings.LastIndex(status, ":")+1:]
+			}
+			if ref, err := props.Object("mPairedStation"); err == nil {
+				paired[port] = ref.PathName
+			}
+			byInfo[port.Info] = port
+		}
+		ports = append(ports, port)
+	}
+
+	for port, pathName := range paired {
+		if info, ok := save.Actor(pathName); ok {
+			port.Paired = byInfo[info]
+		}
+	}
+	return ports, nil
+}
@@ -0,0 +1,127 @@
+package routes_test
+
+import (
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/routes"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestRoutesTestSave(t *testing.T) {
+	save := testsave.Load(t)
+
+	// the test save has no drones or vehicles
+	ports, err := routes.DronePorts(save)
+	if err != nil || len(ports) != 0 {
+		t.Error("Drone ports:", len(ports), err)
+	}
+	vehicles, err := routes.Vehicles(save)
+	if err != nil || len(vehicles) != 0 {
+		t.Error("Vehicles:", len(vehicles), err)
+	}
+}
+
+var ref = testsave.Ref
+
+func TestDronePorts(t *testing.T) {
+	var level testsave.Level
+	level.AddActor("PortA", "Build_DroneStation.Build_DroneStation_C",
+		saveformat.Property{Name: "mInfo", Type: "ObjectProperty", Value: ref("InfoA")},
+		saveformat.Property{Name: "mStationDrone", Type: "ObjectProperty", Value: ref("Drone")},
+		saveformat.Property{Name: "mBatteryInventory", Type: "ObjectProperty", Value: ref("PortA.BatteryInventory")},
+	)
+	level.AddActor("PortB", "Build_DroneStation.Build_DroneStation_C",
+		saveformat.Property{Name: "mInfo", Type: "ObjectProperty", Value: ref("InfoB")},
+	)
+	level.AddActor("InfoA", "FGDroneStationInfo",
+		saveformat.Property{Name: "mPairedStation", Type: "ObjectProperty", Value: ref("InfoB")},
+		saveformat.Property{Name: "mDroneStatus", Type: "EnumProperty", Value: "EDroneStatus::EDS_EN_ROUTE"},
+	)
+	level.AddActor("InfoB", "FGDroneStationInfo")
+	level.AddActor("Drone", "BP_DroneTransport.BP_DroneTransport_C")
+
+	level.AddComponent("PortA.BatteryInventory", "/Script/FactoryGame.FGInventoryComponent", "PortA",
+		saveformat.Property{Name: "mInventoryStacks", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{Value: []any{
+			[]saveformat.Property{
+				{Name: "Item", Type: "StructProperty", InnerType: "InventoryItem", Value: saveformat.InventoryItem{Reference: ref("/Game/Desc_Battery.Desc_Battery_C")}},
+				{Name: "NumItems", Type: "IntProperty", Value: int32(40)},
+			},
+		}}},
+	)
+
+	ports, err := routes.DronePorts(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 2 {
+		t.Fatal("Drone ports:", len(ports))
+	}
+	a, b := ports[0], ports[1]
+	if a.Paired != b || b.Paired != nil {
+		t.Error("Invalid pairing:", a.Paired, b.Paired)
+	}
+	if a.Drone == nil || a.Status != "EDS_EN_ROUTE" || a.Fuel != 40 {
+		t.Error("Invalid drone port:", a.Drone, a.Status, a.Fuel)
+	}
+	if b.Drone != nil || b.Status != "" || b.FuelInventory != nil {
+		t.Error("Invalid idle drone port:", b.Drone, b.Status, b.FuelInventory)
+	}
+}
+
+func TestVehicles(t *testing.T) {
+	var level testsave.Level
+	level.AddActor("Truck", "BP_Truck.BP_Truck_C",
+		saveformat.Property{Name: "mTargetList", Type: "ObjectProperty", Value: ref("TargetList")},
+		saveformat.Property{Name: "mIsSelfDriving", Type: "BoolProperty", Value: byte(1)},
+	)
+	level.AddActor("Tractor", "BP_Tractor.BP_Tractor_C")
+	level.AddActor("TargetList", "FGDrivingTargetList",
+		saveformat.Property{Name: "mFirstTarget", Type: "ObjectProperty", Value: ref("Point0")},
+		saveformat.Property{Name: "mPathName", Type: "StrProperty", Value: "Coal Run"},
+	)
+	level.AddActor("Point0", "BP_VehicleTargetPoint_C",
+		saveformat.Property{Name: "mNext", Type: "ObjectProperty", Value: ref("Point1")},
+		saveformat.Property{Name: "mTargetSpeed", Type: "IntProperty", Value: int32(40)},
+	).PositionX = 100
+	level.AddActor("Point1", "BP_VehicleTargetPoint_C",
+		saveformat.Property{Name: "mNext", Type: "ObjectProperty", Value: ref("Point0")},
+		saveformat.Property{Name: "mWaitTime", Type: "FloatProperty", Value: float32(5)},
+	).PositionY = 200
+
+	// the first point is 19 m from a station, the second one is too far from both
+	stationA := level.AddActor("StationA", "Build_TruckStation.Build_TruckStation_C",
+		saveformat.Property{Name: "mInventory", Type: "ObjectProperty", Value: ref("StationA.Inventory")},
+		saveformat.Property{Name: "mIsInLoadMode", Type: "BoolProperty", Value: byte(1)},
+	)
+	stationA.PositionX, stationA.PositionY = 948, -1700
+	level.AddComponent("StationA.Inventory", "/Script/FactoryGame.FGInventoryComponent", "StationA")
+	level.AddActor("StationB", "Build_TruckStation.Build_TruckStation_C").PositionY = 3000
+
+	vehicles, err := routes.Vehicles(level.Index())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vehicles) != 2 {
+		t.Fatal("Vehicles:", len(vehicles))
+	}
+	truck, tractor := vehicles[0], vehicles[1]
+	if !truck.IsSelfDriving || truck.PathName != "Coal Run" || len(truck.Path) != 2 {
+		t.Fatal("Invalid truck:", truck.IsSelfDriving, truck.PathName, len(truck.Path))
+	}
+	if p := truck.Path[0]; p.X != 100 || p.Speed != 40 {
+		t.Error("Invalid first point:", p)
+	}
+	if p := truck.Path[1]; p.Y != 200 || p.WaitTime != 5 || p.Station != nil {
+		t.Error("Invalid second point:", p)
+	}
+	if station := truck.Path[0].Station; station == nil || station.Actor.Header.Name != "StationA" || !station.IsInLoadMode || station.Inventory == nil {
+		t.Fatal("Invalid station of first point:", station)
+	}
+	if len(truck.Stations) != 1 || truck.Stations[0] != truck.Path[0].Station {
+		t.Error("Stations:", len(truck.Stations))
+	}
+	if tractor.TargetList != nil || tractor.Path != nil {
+		t.Error("Tractor without target list has a path")
+	}
+}
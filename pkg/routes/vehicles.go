@@ -0,0 +1,134 @@
+package routes
+
+import (
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// vehicleClasses are the wheeled vehicles that can drive a recorded path.
+var vehicleClasses = map[string]bool{
+	"BP_Truck_C":        true,
+	"BP_Tractor_C":      true,
+	"BP_Explorer_C":     true,
+	"BP_Golfcart_C":     true,
+	"BP_GolfcartGold_C": true,
+}
+
+// dockingDistance is how close a path point must be to a truck station for the vehicle to dock
+// there, in cm. The save does not link path points to stations.
+const dockingDistance = 2000
+
+// PathPoint is a recorded target point of a vehicle path.
+type PathPoint struct {
+	Actor    *saveformat.Actor
+	X, Y, Z  float32
+	Speed    int                   // target speed, km/h
+	WaitTime float32               // seconds to wait at the point
+	Station  *model.VehicleStation // nil when the point is not at a truck station
+}
+
+// Vehicle is a wheeled vehicle with its recorded path.
+type Vehicle struct {
+	Actor         *saveformat.Actor
+	IsSelfDriving bool
+	TargetList    *saveformat.Actor // FGDrivingTargetList, nil when no path is recorded
+	PathName      string
+	Path          []PathPoint
+	Stations      []*model.VehicleStation // stations along the path in driving order
+}
+
+// Vehicles returns the wheeled vehicles of the save. The path of a target list is
+// followed from its first target point through the next point of each point, a point
+// within docking distance of a truck station stops at the nearest one.
+func Vehicles(save *saveformat.SaveIndex) ([]*Vehicle, error) {
+	stations, err := VehicleStations(save)
+	if err != nil {
+		return nil, err
+	}
+
+	var vehicles []*Vehicle
+	for _, actor := range save.Actors {
+		if !vehicleClasses[model.ClassName(actor.Header.TypePath)] {
+			continue
+		}
+		props, err := actor.DecodedProperties()
+		if err != nil {
+			return nil, err
+		}
+
+		vehicle := &Vehicle{Actor: actor, TargetList: model.ActorProperty(save, props, "mTargetList")}
+		vehicle.IsSelfDriving, _ = props.Bool("mIsSelfDriving")
+		if vehicle.TargetList != nil {
+			if err := vehicle.readPath(save, stations); err != nil {
+				return nil, err
+			}
+		}
+		vehicles = append(vehicles, vehicle)
+	}
+	return vehicles, nil
+}
+
+// VehicleStations returns the truck stations of the save.
+func VehicleStations(save *saveformat.SaveIndex) ([]*model.VehicleStation, error) {
+	var stations []*model.VehicleStation
+	for _, actor := range save.Actors {
+		if model.FamilyOf(actor.Header.TypePath) != model.FamilyVehicleStation {
+			continue
+		}
+		station, err := model.NewVehicleStation(save, actor)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
+
+func (vehicle *Vehicle) readPath(save *saveformat.SaveIndex, stations []*model.VehicleStation) error {
+	props, err := vehicle.TargetList.DecodedProperties()
+	if err != nil {
+		return err
+	}
+	vehicle.PathName, _ = props.Str("mPathName")
+
+	visited := make(map[*saveformat.Actor]bool)
+	for point := model.ActorProperty(save, props, "mFirstTarget"); point != nil && !visited[point]; {
+		visited[point] = true
+		props, err := point.DecodedProperties()
+		if err != nil {
+			return err
+		}
+
+		speed, _ := props.Int("mTargetSpeed")
+		wait, _ := props.Float("mWaitTime")
+		pathPoint := PathPoint{
+			Actor:    point,
+			X:        point.Header.PositionX,
+			Y:        point.Header.PositionY,
+			Z:        point.Header.PositionZ,
+			Speed:    int(speed),
+			WaitTime: float32(wait),
+			Station:  nearestStation(stations, point),
+		}
+		vehicle.Path = append(vehicle.Path, pathPoint)
+		if station := pathPoint.Station; station != nil && (len(vehicle.Stations) == 0 || vehicle.Stations[len(vehicle.Stations)-1] != station) {
+			vehicle.Stations = append(vehicle.Stations, station)
+		}
+		point = model.ActorProperty(save, props, "mNext")
+	}
+	return nil
+}
+
+// nearestStation returns the nearest station within docking distance of a path point.
+func nearestStation(stations []*model.VehicleStation, point *saveformat.Actor) *model.VehicleStation {
+	position := geometry.ActorTransform(point.Header).Position()
+	var nearest *model.VehicleStation
+	distance := float64(dockingDistance)
+	for _, station := range stations {
+		if d := geometry.ActorTransform(station.Actor.Header).Position().Distance(position); d <= distance {
+			nearest, distance = station, d
+		}
+	}
+	return nearest
+}
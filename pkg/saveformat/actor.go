@@ -0,0 +1,105 @@
+package saveformat
+
+// Actor joins an actor header with its object and components.
+type Actor struct {
+	Header     *ActorHeader
+	Object     *ActorObject // nil when the level was read without objects
+	Components []*Component
+}
+
+// Component joins a component header with its object and parent actor.
+type Component struct {
+	Header *ComponentHeader
+	Object *ComponentObject // nil when the level was read without objects
+	Parent *Actor           // owning actor, also for components nested in other components
+}
+
+// Actors links the headers and objects of the level into actors with their components.
+func (level *LevelData) Actors() []*Actor {
+	actors, _ := linkLevels([]LevelData{*level})
+	return actors
+}
+
+// Actors links the headers and objects of all levels into actors with their components.
+func (body *SaveFileBody) Actors() []*Actor {
+	actors, _ := linkLevels(body.Levels)
+	return actors
+}
+
+// linkLevels returns all actors and all components, including components without a parent actor.
+func linkLevels(levels []LevelData) ([]*Actor, []*Component) {
+	var actors []*Actor
+	var components []*Component
+	actorsByName := make(map[string]*Actor)
+
+	for l := range levels {
+		level := &levels[l]
+		for i := range level.ActorHeaders {
+			actor := &Actor{Header: &level.ActorHeaders[i]}
+			if i < len(level.ActorObjects) {
+				actor.Object = &level.ActorObjects[i]
+			}
+			actors = append(actors, actor)
+			actorsByName[actor.Header.Name] = actor
+		}
+
+		for i := range level.ComponentHeaders {
+			component := &Component{Header: &level.ComponentHeaders[i]}
+			if i < len(level.ComponentObjects) {
+				component.Object = &level.ComponentObjects[i]
+			}
+			components = append(components, component)
+		}
+	}
+
+	componentsByName := make(map[string]*Component, len(components))
+	for _, component := range components {
+		componentsByName[component.Header.Name] = component
+	}
+
+	// keep the component order of the actor object, then add any component that only names its parent
+	for _, actor := range actors {
+		if actor.Object == nil {
+			continue
+		}
+		for _, reference := range actor.Object.Components {
+			component, ok := componentsByName[reference.PathName]
+			if ok && component.Parent == nil {
+				component.Parent = actor
+				actor.Components = append(actor.Components, component)
+			}
+		}
+	}
+	for _, component := range components {
+		if component.Parent != nil {
+			continue
+		}
+		if actor := owningActor(component, actorsByName, componentsByName); actor != nil {
+			component.Parent = actor
+			actor.Components = append(actor.Components, component)
+		}
+	}
+
+	return actors, components
+}
+
+// owningActor follows the parent names of a component, which can be nested in other components
+// at any depth. A chain that loops back on itself has no owning actor.
+func owningActor(component *Component, actorsByName map[string]*Actor, componentsByName map[string]*Component) *Actor {
+	visited := map[string]bool{component.Header.Name: true}
+	for name := component.Header.ParentActorName; !visited[name]; {
+		visited[name] = true
+		if actor, ok := actorsByName[name]; ok {
+			return actor
+		}
+		parent, ok := componentsByName[name]
+		if !ok {
+			return nil
+		}
+		if parent.Parent != nil {
+			return parent.Parent
+		}
+		name = parent.Header.ParentActorName
+	}
+	return nil
+}
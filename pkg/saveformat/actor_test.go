@@ -0,0 +1,65 @@
+package saveformat_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestActors(t *testing.T) {
+	body := testsave.LoadBody(t)
+
+	actors := body.Actors()
+	wantActors, wantComponents := 0, 0
+	for _, level := range body.Levels {
+		wantActors += len(level.ActorHeaders)
+		wantComponents += len(level.ComponentHeaders)
+	}
+	if len(actors) != wantActors {
+		t.Fatal("Actors:", len(actors), "want", wantActors)
+	}
+
+	components := 0
+	for _, actor := range actors {
+		if actor.Object == nil {
+			t.Fatal("Actor without object:", actor.Header.Name)
+		}
+		for _, component := range actor.Components {
+			if component.Parent != actor || !strings.HasPrefix(component.Header.Name, actor.Header.Name) {
+				t.Error("Component linked to wrong actor:", component.Header.Name)
+			}
+			components++
+		}
+	}
+	if components != wantComponents {
+		t.Error("Linked components:", components, "want", wantComponents)
+	}
+}
+
+func TestActorsNestedComponents(t *testing.T) {
+	var level testsave.Level
+	level.AddActor("Actor", "Build_Actor.Build_Actor_C")
+	parent := "Actor"
+	for i := range 12 {
+		name := fmt.Sprintf("Component%d", i)
+		level.AddComponent(name, "/Script/Engine.SceneComponent", parent)
+		parent = name
+	}
+	// parents that loop back on each other have no owning actor
+	level.AddComponent("LoopA", "/Script/Engine.SceneComponent", "LoopB")
+	level.AddComponent("LoopB", "/Script/Engine.SceneComponent", "LoopA")
+
+	body := saveformat.SaveFileBody{Levels: []saveformat.LevelData{level.LevelData}}
+	actors := body.Actors()
+	if len(actors) != 1 || len(actors[0].Components) != 12 {
+		t.Fatal("Components of nested chain:", len(actors[0].Components))
+	}
+	for _, component := range actors[0].Components {
+		if strings.HasPrefix(component.Header.Name, "Loop") {
+			t.Error("Looping component linked:", component.Header.Name)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package saveformat
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Entity is a resolved actor or component.
+type Entity interface {
+	PathName() string
+	TypePath() string
+	// Owner is the actor itself, or the actor owning the component.
+	Owner() *Actor
+	// DecodedProperties returns the properties of the object, decoding them if they were read lazily.
+	DecodedProperties() (Properties, error)
+}
+
+func (a *Actor) PathName() string { return a.Header.Name }
+func (a *Actor) TypePath() string { return a.Header.TypePath }
+func (a *Actor) Owner() *Actor    { return a }
+
+func (a *Actor) DecodedProperties() (Properties, error) {
+	if a.Object == nil {
+		return nil, nil
+	}
+	return a.Object.DecodeProperties()
+}
+
+func (c *Component) PathName() string { return c.Header.Name }
+func (c *Component) TypePath() string { return c.Header.TypePath }
+func (c *Component) Owner() *Actor    { return c.Parent }
+
+func (c *Component) DecodedProperties() (Properties, error) {
+	if c.Object == nil {
+		return nil, nil
+	}
+	return c.Object.DecodeProperties()
+}
+
+// ObjectReferencer is implemented by decoded struct values that hold an object reference.
+type ObjectReferencer interface {
+	ObjectReference() ObjectReference
+}
+
+// Reference is a reference from an entity to an object.
+type Reference struct {
+	From     Entity
+	Property string // top level property holding the reference, empty for the actor component list
+}
+
+// SaveIndex maps the path names of all actors and components in a save to their entities.
+type SaveIndex struct {
+	Actors     []*Actor
+	Components []*Component
+	// Collected are the destroyed level actors, like picked up collectibles, of all levels.
+	// Levels list them before and after their objects, they are only kept once.
+	Collected []ObjectReference
+
+	entities     map[string]Entity
+	collected    map[string]struct{}
+	referencedBy map[string][]Reference
+}
+
+// NewSaveIndex links and indexes the actors and components of all levels.
+func NewSaveIndex(body *SaveFileBody) *SaveIndex {
+	actors, components := linkLevels(body.Levels)
+	index := &SaveIndex{
+		Actors:     actors,
+		Components: components,
+		entities:   make(map[string]Entity, len(actors)+len(components)),
+		collected:  make(map[string]struct{}),
+	}
+	for _, actor := range actors {
+		index.entities[actor.Header.Name] = actor
+	}
+	for _, component := range components {
+		index.entities[component.Header.Name] = component
+	}
+	for _, level := range body.Levels {
+		for _, ref := range slices.Concat(level.Collectables, level.SecondCollectables) {
+			if ref.PathName != "" && !index.IsCollected(ref.PathName) {
+				index.Collected = append(index.Collected, ref)
+				index.collected[ref.PathName] = struct{}{}
+			}
+		}
+	}
+	return index
+}
+
+// IsCollected reports whether the level actor with the given path name was destroyed.
+func (index *SaveIndex) IsCollected(pathName string) bool {
+	_, ok := index.collected[pathName]
+	return ok
+}
+
+// Resolve returns the actor or component the reference points to.
+func (index *SaveIndex) Resolve(ref ObjectReference) (Entity, bool) {
+	return index.Lookup(ref.PathName)
+}
+
+// Lookup returns the actor or component with the given path name.
+func (index *SaveIndex) Lookup(pathName string) (Entity, bool) {
+	entity, ok := index.entities[pathName]
+	return entity, ok
+}
+
+// Actor returns the actor with the given path name.
+func (index *SaveIndex) Actor(pathName string) (*Actor, bool) {
+	actor, ok := index.entities[pathName].(*Actor)
+	return actor, ok
+}
+
+// Component returns the component with the given path name.
+func (index *SaveIndex) Component(pathName string) (*Component, bool) {
+	component, ok := index.entities[pathName].(*Component)
+	return component, ok
+}
+
+// ReferencedBy returns the entities with a reference to the given path name, in save order.
+// The reverse index is built on the first call, decoding lazy properties as needed.
+// It is not safe for concurrent use.
+func (index *SaveIndex) ReferencedBy(pathName string) []Reference {
+	if index.referencedBy == nil {
+		index.buildReferencedBy()
+	}
+	return index.referencedBy[pathName]
+}
+
+func (index *SaveIndex) buildReferencedBy() {
+	index.referencedBy = make(map[string][]Reference)
+	add := func(from Entity, property string) func(ref ObjectReference) {
+		return func(ref ObjectReference) {
+			if ref.PathName != "" {
+				index.referencedBy[ref.PathName] = append(index.referencedBy[ref.PathName], Reference{From: from, Property: property})
+			}
+		}
+	}
+
+	addProperties := func(from Entity) {
+		props, err := from.DecodedProperties()
+		if err != nil {
+			return
+		}
+		for _, prop := range props {
+			forEachReference(prop.Value, add(from, prop.Name))
+		}
+	}
+
+	for _, actor := range index.Actors {
+		if actor.Object != nil {
+			addToParent := add(actor, "")
+			addToParent(actor.Object.ParentReference)
+			for _, ref := range actor.Object.Components {
+				addToParent(ref)
+			}
+		}
+		addProperties(actor)
+	}
+	for _, component := range index.Components {
+		addProperties(component)
+	}
+}
+
+// forEachReference calls fn for every object reference in a decoded property value.
+func forEachReference(value any, fn func(ObjectReference)) {
+	switch v := value.(type) {
+	case nil:
+	case ObjectReference:
+		fn(v)
+	case []ObjectReference:
+		for _, ref := range v {
+			fn(ref)
+		}
+	case ObjectReferencer:
+		fn(v.ObjectReference())
+	case []Property:
+		for _, prop := range v {
+			forEachReference(prop.Value, fn)
+		}
+	case ArrayStructProperty:
+		for _, element := range v.Value {
+			forEachReference(element, fn)
+		}
+	case InventoryItem:
+		fn(v.Reference)
+		for _, prop := range v.Properties {
+			forEachReference(prop.Value, fn)
+		}
+	default:
+		// arrays of struct values holding references, e.g. soft object references
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(reflect.TypeFor[ObjectReferencer]()) {
+			return
+		}
+		for i := range rv.Len() {
+			fn(rv.Index(i).Interface().(ObjectReferencer).ObjectReference())
+		}
+	}
+}
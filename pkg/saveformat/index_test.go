@@ -0,0 +1,56 @@
+package saveformat_test
+
+import (
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+func TestSaveIndex(t *testing.T) {
+	index := testsave.Load(t)
+
+	powerInfos := 0
+	for _, actor := range index.Actors {
+		for _, prop := range actor.Object.Properties {
+			if prop.Name != "mPowerInfo" {
+				continue
+			}
+			ref := prop.Value.(saveformat.ObjectReference)
+			powerInfo, ok := index.Resolve(ref)
+			if !ok {
+				t.Fatal("Unresolved power info:", ref.PathName)
+			}
+			if powerInfo.Owner() != actor {
+				t.Fatal("Power info with wrong owner:", ref.PathName)
+			}
+
+			found := false
+			for _, reference := range index.ReferencedBy(ref.PathName) {
+				found = found || (reference.From == saveformat.Entity(actor) && reference.Property == "mPowerInfo")
+			}
+			if !found {
+				t.Fatal("Power info missing from reverse lookup:", ref.PathName)
+			}
+			powerInfos++
+		}
+	}
+	if powerInfos == 0 {
+		t.Fatal("No power infos found")
+	}
+
+	for _, actor := range index.Actors {
+		for _, component := range actor.Components {
+			if owner := component.Owner(); owner != actor {
+				t.Fatal("Wrong owner for", component.PathName())
+			}
+		}
+	}
+
+	if len(index.Collected) != 5 || !index.IsCollected("Persistent_Level:PersistentLevel.BP_WAT11_1") {
+		t.Error("Collected:", index.Collected)
+	}
+	if index.IsCollected("Persistent_Level:PersistentLevel.BP_WAT18") {
+		t.Error("Somersloop in the level is collected")
+	}
+}
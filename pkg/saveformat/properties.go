@@ -0,0 +1,264 @@
+package saveformat
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPropertyNotFound = errors.New("property not found")
+	ErrPropertyType     = errors.New("property has a different type")
+)
+
+// Properties is a decoded property list with typed accessors.
+type Properties []Property
+
+// Get returns the first property with the given name.
+func (props Properties) Get(name string) (Property, error) {
+	for _, prop := range props {
+		if prop.Name == name {
+			return prop, nil
+		}
+	}
+	return Property{}, fmt.Errorf("%s: %w", name, ErrPropertyNotFound)
+}
+
+// Int returns the value of any integer property.
+func (props Properties) Int(name string) (int64, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := prop.Value.(type) {
+	case int8:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint32:
+		return int64(v), nil
+	case byte:
+		if prop.Type == "ByteProperty" {
+			return int64(v), nil
+		}
+	}
+	return 0, typeError(name, prop, "integer")
+}
+
+// Float returns the value of a float or double property.
+func (props Properties) Float(name string) (float64, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := prop.Value.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, typeError(name, prop, "float")
+}
+
+func (props Properties) Bool(name string) (bool, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return false, err
+	}
+
+	if v, ok := prop.Value.(byte); ok && prop.Type == "BoolProperty" {
+		return v != 0, nil
+	}
+	return false, typeError(name, prop, "bool")
+}
+
+// Str returns the value of a string, name or enum property.
+func (props Properties) Str(name string) (string, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	if v, ok := prop.Value.(string); ok {
+		return v, nil
+	}
+	return "", typeError(name, prop, "string")
+}
+
+// Object returns the reference of an object, interface or soft object property.
+func (props Properties) Object(name string) (ObjectReference, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return ObjectReference{}, err
+	}
+
+	switch v := prop.Value.(type) {
+	case ObjectReference:
+		return v, nil
+	case ObjectReferencer:
+		return v.ObjectReference(), nil
+	}
+	return ObjectReference{}, typeError(name, prop, "object")
+}
+
+// Struct returns the properties of a struct property. Structs with a fixed layout,
+// like vectors, are not property lists and are returned by Get.
+func (props Properties) Struct(name string) (Properties, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := prop.Value.([]Property); ok {
+		return v, nil
+	}
+	return nil, typeError(name, prop, "struct")
+}
+
+// Array returns the elements of an array property.
+func (props Properties) Array(name string) ([]any, error) {
+	prop, err := props.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if prop.Type == "ArrayProperty" {
+		if elements, ok := arrayElements(prop.Value); ok {
+			return elements, nil
+		}
+	}
+	return nil, typeError(name, prop, "array")
+}
+
+// Lookup returns the value at a path of property names, array indices and struct fields,
+// e.g. "mInventoryStacks[3].Item.ItemClass".
+func (props Properties) Lookup(path string) (any, error) {
+	var value any = props
+	walked := ""
+	for _, segment := range strings.Split(path, ".") {
+		name, indices, err := parsePathSegment(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		walked = joinPath(walked, name)
+		if value, err = field(value, name); err != nil {
+			return nil, fmt.Errorf("%s: %w", walked, err)
+		}
+
+		for _, i := range indices {
+			walked += "[" + strconv.Itoa(i) + "]"
+			elements, ok := arrayElements(value)
+			if !ok {
+				return nil, fmt.Errorf("%s: not an array: %w", walked, ErrPropertyType)
+			}
+			if i >= len(elements) {
+				return nil, fmt.Errorf("%s: index out of range [%d]: %w", walked, len(elements), ErrPropertyNotFound)
+			}
+			value = elements[i]
+		}
+	}
+	return value, nil
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// parsePathSegment splits "name[1][2]" into its name and indices.
+func parsePathSegment(segment string) (string, []int, error) {
+	name, rest, hasIndex := strings.Cut(segment, "[")
+	if name == "" || (hasIndex && rest == "") {
+		return "", nil, fmt.Errorf("invalid path segment %q", segment)
+	}
+
+	var indices []int
+	for rest != "" {
+		index, after, ok := strings.Cut(rest, "]")
+		i, err := strconv.Atoi(index)
+		if !ok || err != nil || i < 0 {
+			return "", nil, fmt.Errorf("invalid index in path segment %q", segment)
+		}
+		indices = append(indices, i)
+		if rest = strings.TrimPrefix(after, "["); rest == after && after != "" {
+			return "", nil, fmt.Errorf("invalid path segment %q", segment)
+		}
+	}
+	return name, indices, nil
+}
+
+// field returns a named value of a property list or a decoded struct value.
+func field(value any, name string) (any, error) {
+	switch v := value.(type) {
+	case Properties:
+		return propertyValue(v, name)
+	case []Property:
+		return propertyValue(v, name)
+	case InventoryItem:
+		if name == "ItemClass" {
+			return v.Reference, nil
+		}
+		if _, ok := reflect.TypeFor[InventoryItem]().FieldByName(name); !ok {
+			return propertyValue(v.Properties, name)
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%T has no fields: %w", value, ErrPropertyType)
+	}
+	f := rv.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return nil, fmt.Errorf("%T has no field %s: %w", value, name, ErrPropertyNotFound)
+	}
+	return f.Interface(), nil
+}
+
+func propertyValue(props Properties, name string) (any, error) {
+	for _, prop := range props {
+		if prop.Name != name {
+			continue
+		}
+		if prop.Value == nil && prop.Raw != nil {
+			return nil, fmt.Errorf("%s is not decoded: %w", prop.Type, ErrPropertyType)
+		}
+		return prop.Value, nil
+	}
+	return nil, ErrPropertyNotFound
+}
+
+// arrayElements returns the elements of a decoded array value.
+func arrayElements(value any) ([]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		return v, true
+	case ArrayStructProperty:
+		return v.Value, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elements := make([]any, rv.Len())
+	for i := range elements {
+		elements[i] = rv.Index(i).Interface()
+	}
+	return elements, true
+}
+
+func typeError(name string, prop Property, want string) error {
+	if prop.Value == nil && prop.Raw != nil {
+		return fmt.Errorf("%s: %s is not decoded: %w", name, prop.Type, ErrPropertyType)
+	}
+	return fmt.Errorf("%s: %s is not %s: %w", name, prop.Type, want, ErrPropertyType)
+}
@@ -0,0 +1,102 @@
+package saveformat_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+var testProperties = saveformat.Properties{
+	{Name: "mCount", Type: "IntProperty", Value: int32(42)},
+	{Name: "mPotential", Type: "FloatProperty", Value: float32(1.5)},
+	{Name: "mIsProducing", Type: "BoolProperty", Value: byte(1)},
+	{Name: "mInventory", Type: "ObjectProperty", Value: saveformat.ObjectReference{LevelName: "Persistent_Level", PathName: "Inventory"}},
+	{Name: "mSettings", Type: "StructProperty", InnerType: "Settings", Value: []saveformat.Property{
+		{Name: "mName", Type: "StrProperty", Value: "Iron"},
+	}},
+	{Name: "mSlotSizes", Type: "ArrayProperty", InnerType: "IntProperty", Value: []int32{1, 2, 3}},
+	{Name: "mInventoryStacks", Type: "ArrayProperty", InnerType: "StructProperty", Value: saveformat.ArrayStructProperty{
+		Value: []any{
+			[]saveformat.Property{
+				{Name: "Item", Type: "StructProperty", Value: saveformat.InventoryItem{Reference: saveformat.ObjectReference{PathName: "Desc_IronPlate_C"}}},
+				{Name: "NumItems", Type: "IntProperty", Value: int32(100)},
+			},
+		},
+	}},
+	{Name: "mNames", Type: "MapProperty", Raw: []byte{1, 2, 3}},
+}
+
+func TestPropertyAccessors(t *testing.T) {
+	if v, err := testProperties.Int("mCount"); err != nil || v != 42 {
+		t.Error("Int:", v, err)
+	}
+	if v, err := testProperties.Float("mPotential"); err != nil || v != 1.5 {
+		t.Error("Float:", v, err)
+	}
+	if v, err := testProperties.Bool("mIsProducing"); err != nil || !v {
+		t.Error("Bool:", v, err)
+	}
+	if v, err := testProperties.Object("mInventory"); err != nil || v.PathName != "Inventory" {
+		t.Error("Object:", v, err)
+	}
+	if v, err := testProperties.Struct("mSettings"); err != nil || len(v) != 1 {
+		t.Error("Struct:", v, err)
+	}
+	if v, err := testProperties.Array("mSlotSizes"); err != nil || len(v) != 3 || v[2] != int32(3) {
+		t.Error("Array:", v, err)
+	}
+
+	if _, err := testProperties.Int("mMissing"); !errors.Is(err, saveformat.ErrPropertyNotFound) {
+		t.Error("Missing property error:", err)
+	}
+	if _, err := testProperties.Float("mCount"); !errors.Is(err, saveformat.ErrPropertyType) {
+		t.Error("Type mismatch error:", err)
+	}
+	if _, err := testProperties.Array("mNames"); !errors.Is(err, saveformat.ErrPropertyType) {
+		t.Error("Raw property error:", err)
+	}
+}
+
+func TestPropertyLookup(t *testing.T) {
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"mCount", int32(42)},
+		{"mSettings.mName", "Iron"},
+		{"mSlotSizes[1]", int32(2)},
+		{"mInventoryStacks[0].NumItems", int32(100)},
+		{"mInventoryStacks[0].Item.ItemClass.PathName", "Desc_IronPlate_C"},
+		{"mInventory.PathName", "Inventory"},
+	}
+	for _, test := range tests {
+		got, err := testProperties.Lookup(test.path)
+		if err != nil || got != test.want {
+			t.Error("Lookup", test.path, "got", got, err, "want", test.want)
+		}
+	}
+
+	errorTests := []struct {
+		path string
+		want error
+	}{
+		{"mMissing", saveformat.ErrPropertyNotFound},
+		{"mSlotSizes[3]", saveformat.ErrPropertyNotFound},
+		{"mSettings.mMissing", saveformat.ErrPropertyNotFound},
+		{"mCount[0]", saveformat.ErrPropertyType},
+		{"mCount.X", saveformat.ErrPropertyType},
+		{"mNames", saveformat.ErrPropertyType},
+	}
+	for _, test := range errorTests {
+		if _, err := testProperties.Lookup(test.path); !errors.Is(err, test.want) {
+			t.Error("Lookup", test.path, "error", err, "want", test.want)
+		}
+	}
+
+	for _, path := range []string{"", "mSlotSizes[", "mSlotSizes[x]", "mSlotSizes[1]x", "a..b"} {
+		if _, err := testProperties.Lookup(path); err == nil {
+			t.Error("Lookup of invalid path succeeded:", path)
+		}
+	}
+}
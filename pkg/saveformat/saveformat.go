@@ -37,8 +37,21 @@ type SaveFileBody struct {
 	Zero               uint32
 	ReferenceListCount uint32
 	References         []ObjectReference
+	StringEncodings    StringEncodings
 }
 
+// StringEncoding is how a string is saved, UTF-8 with a positive length or UTF-16 LE with a negative one.
+type StringEncoding byte
+
+const (
+	EncodingUTF8 StringEncoding = iota + 1
+	EncodingUTF16
+)
+
+// StringEncodings are the strings saved in another encoding than the default, which is UTF-8 for
+// ASCII strings and UTF-16 for the others. Properties decoded lazily after parsing are not recorded.
+type StringEncodings map[string]StringEncoding
+
 type LevelGroupingGrid struct {
 	GridName   string
 	Unknown1   uint32
@@ -56,14 +69,19 @@ type LevelData struct {
 	Name                   string
 	Size                   uint64
 	HeaderCount            uint32
+	HeaderTypes            []uint32 // 0 for a component, 1 for an actor, in file order
 	ActorHeaders           []ActorHeader
 	ComponentHeaders       []ComponentHeader
+	HasCollectables        bool
+	CollectablesLevelCount uint32 // only present on the persistent level, precedes the level name
+	CollectablesLevelName  string // only present on the persistent level
 	CollectableCount       uint32
 	Collectables           []ObjectReference
 	ObjectSize             uint64
 	ObjectCount            uint32
 	ActorObjects           []ActorObject
 	ComponentObjects       []ComponentObject
+	SaveVersion            uint32 // only present on sub levels, version >= 51
 	SecondCollectableCount uint32
 	SecondCollectables     []ObjectReference
 }
@@ -95,6 +113,8 @@ type ActorObject struct {
 	ComponentCount  uint32
 	Components      []ObjectReference
 	Properties      []Property
+	Trailing        []byte
+	Lazy            *LazyProperties // set instead of Properties and Trailing until decoded
 }
 
 type ComponentHeader struct {
@@ -111,12 +131,25 @@ type ComponentObject struct {
 	Size        uint32
 	Properties  []Property
 	Zero        uint32
+	Trailing    []byte
+	Lazy        *LazyProperties // set instead of Properties and Trailing until decoded
+}
+
+// LazyProperties is the undecoded property block of an object, followed by its trailing bytes.
+type LazyProperties struct {
+	Data   []byte
+	Decode func(data []byte) (props []Property, trailing []byte, err error)
 }
 
 type Property struct {
-	Name  string
-	Type  string
-	Value any
+	Name      string
+	Type      string
+	Index     uint32
+	InnerType string   // enum, struct or element type, key type for maps
+	ValueType string   // value type for maps
+	GUID      [16]byte // struct GUID of struct properties
+	Value     any
+	Raw       []byte // undecoded payload of property types we skip
 }
 
 type ObjectReference struct {
@@ -133,16 +166,52 @@ func (body *SaveFileBody) IsValid() bool {
 }
 
 func (a *ActorObject) IsValid() bool {
-	lastProp := a.Properties[len(a.Properties)-1]
 	return (a.Flag == 0 || a.Flag == 1) &&
 		a.Size > 0 &&
-		lastProp.Name == "None" && lastProp.Type == ""
+		(a.Lazy != nil || endsWithNone(a.Properties))
 }
 func (c *ComponentObject) IsValid() bool {
-	lastProp := c.Properties[len(c.Properties)-1]
 	return (c.Flag == 0 || c.Flag == 1) &&
 		c.Size > 0 && c.Zero == 0 &&
-		lastProp.Name == "None" && lastProp.Type == ""
+		(c.Lazy != nil || endsWithNone(c.Properties))
+}
+
+func endsWithNone(props []Property) bool {
+	if len(props) == 0 {
+		return false
+	}
+	lastProp := props[len(props)-1]
+	return lastProp.Name == "None" && lastProp.Type == ""
+}
+
+// DecodeProperties decodes the properties of an actor read with lazy properties.
+// Already decoded properties are returned as is. It is not safe for concurrent use.
+func (a *ActorObject) DecodeProperties() ([]Property, error) {
+	if a.Lazy == nil {
+		return a.Properties, nil
+	}
+
+	props, trailing, err := a.Lazy.Decode(a.Lazy.Data)
+	if err != nil {
+		return nil, err
+	}
+	a.Properties, a.Trailing, a.Lazy = props, trailing, nil
+	return a.Properties, nil
+}
+
+// DecodeProperties decodes the properties of a component read with lazy properties.
+// Already decoded properties are returned as is. It is not safe for concurrent use.
+func (c *ComponentObject) DecodeProperties() ([]Property, error) {
+	if c.Lazy == nil {
+		return c.Properties, nil
+	}
+
+	props, trailing, err := c.Lazy.Decode(c.Lazy.Data)
+	if err != nil {
+		return nil, err
+	}
+	c.Properties, c.Trailing, c.Lazy = props, trailing, nil
+	return c.Properties, nil
 }
 
 type ArrayStructProperty struct {
@@ -157,6 +226,7 @@ type ArrayStructProperty struct {
 	Padding4    uint32
 	PaddingByte byte
 	Value       []any
+	Trailing    []byte // bytes after the elements, within Size
 }
 
 type InventoryItem struct {
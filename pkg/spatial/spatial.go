@@ -0,0 +1,208 @@
+// Package spatial indexes the actors of a save by position for region and nearest queries.
+package spatial
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+)
+
+// CellSize is the width of a grid cell of the index, 100 m.
+const CellSize = 10000
+
+// Filter selects the actors of a query, all filters of a query must match.
+type Filter func(actor *saveformat.Actor) bool
+
+// Classes matches actors of any of the classes, e.g. Build_ConstructorMk1_C.
+func Classes(classes ...string) Filter {
+	return func(actor *saveformat.Actor) bool {
+		return slices.Contains(classes, model.ClassName(actor.Header.TypePath))
+	}
+}
+
+// ClassPrefix matches actors whose class starts with prefix, e.g. Build_ for all buildings.
+func ClassPrefix(prefix string) Filter {
+	return func(actor *saveformat.Actor) bool {
+		return strings.HasPrefix(model.ClassName(actor.Header.TypePath), prefix)
+	}
+}
+
+// Families matches buildings of any of the families.
+func Families(families ...model.Family) Filter {
+	return func(actor *saveformat.Actor) bool {
+		return slices.Contains(families, model.FamilyOf(actor.Header.TypePath))
+	}
+}
+
+// Result is an actor found by a query with its position and distance to the query point,
+// the distance is zero for box queries.
+type Result struct {
+	Actor    *saveformat.Actor
+	Position geometry.Vector
+	Distance float64
+}
+
+type entry struct {
+	order    int // save order, to return box queries in save order
+	actor    *saveformat.Actor
+	position geometry.Vector
+}
+
+type cell struct {
+	x, y int
+}
+
+// Index is a grid over the map positions of actors, the height is only used to filter.
+type Index struct {
+	cells    map[cell][]entry
+	min, max cell // bounds of the occupied cells
+	size     int
+}
+
+// NewIndex indexes the actors of the save. Actors at the origin are left out, these are
+// managers and other actors without a place in the world.
+func NewIndex(save *saveformat.SaveIndex) *Index {
+	index := &Index{cells: make(map[cell][]entry)}
+	for i, actor := range save.Actors {
+		position := geometry.ActorTransform(actor.Header).Position()
+		if position == (geometry.Vector{}) {
+			continue
+		}
+
+		c := cellOf(position)
+		if index.size == 0 {
+			index.min, index.max = c, c
+		}
+		index.min = cell{min(index.min.x, c.x), min(index.min.y, c.y)}
+		index.max = cell{max(index.max.x, c.x), max(index.max.y, c.y)}
+		index.cells[c] = append(index.cells[c], entry{order: i, actor: actor, position: position})
+		index.size++
+	}
+	return index
+}
+
+// Len returns the number of indexed actors.
+func (index *Index) Len() int {
+	return index.size
+}
+
+func cellOf(v geometry.Vector) cell {
+	return cell{int(math.Floor(v.X / CellSize)), int(math.Floor(v.Y / CellSize))}
+}
+
+// visit calls fn for the matching entries in the cells from lo to hi, clamped to the index bounds.
+func (index *Index) visit(lo, hi cell, filters []Filter, fn func(entry)) {
+	lo = cell{max(lo.x, index.min.x), max(lo.y, index.min.y)}
+	hi = cell{min(hi.x, index.max.x), min(hi.y, index.max.y)}
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			index.visitCell(cell{x, y}, filters, fn)
+		}
+	}
+}
+
+func (index *Index) visitCell(c cell, filters []Filter, fn func(entry)) {
+	for _, e := range index.cells[c] {
+		if matches(e.actor, filters) {
+			fn(e)
+		}
+	}
+}
+
+func matches(actor *saveformat.Actor, filters []Filter) bool {
+	for _, filter := range filters {
+		if !filter(actor) {
+			return false
+		}
+	}
+	return true
+}
+
+// Within returns the actors inside the box in save order.
+func (index *Index) Within(box geometry.AABB, filters ...Filter) []Result {
+	var entries []entry
+	index.visit(cellOf(box.Min), cellOf(box.Max), filters, func(e entry) {
+		if box.Contains(e.position) {
+			entries = append(entries, e)
+		}
+	})
+	slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.order, b.order) })
+
+	results := make([]Result, 0, len(entries))
+	for _, e := range entries {
+		results = append(results, Result{Actor: e.actor, Position: e.position})
+	}
+	return results
+}
+
+// InRadius returns the actors within radius of point, nearest first.
+func (index *Index) InRadius(point geometry.Vector, radius float64, filters ...Filter) []Result {
+	var results []Result
+	extent := geometry.Vector{X: radius, Y: radius}
+	index.visit(cellOf(point.Sub(extent)), cellOf(point.Add(extent)), filters, func(e entry) {
+		if d := point.Distance(e.position); d <= radius {
+			results = append(results, Result{Actor: e.actor, Position: e.position, Distance: d})
+		}
+	})
+	sortByDistance(results)
+	return results
+}
+
+// Nearest returns the k actors nearest to point, nearest first.
+func (index *Index) Nearest(point geometry.Vector, k int, filters ...Filter) []Result {
+	if k <= 0 || index.size == 0 {
+		return nil
+	}
+
+	// search rings of cells around the cell of point, until the k nearest found so far are
+	// closer than anything in the next ring. Rings start at the first one reaching the occupied
+	// cells and only their cells inside the bounds are visited, so points far off the map
+	// cost no more than points on it.
+	center := cellOf(point)
+	first := max(0, index.min.x-center.x, center.x-index.max.x, index.min.y-center.y, center.y-index.max.y)
+	last := max(abs(center.x-index.min.x), abs(center.x-index.max.x), abs(center.y-index.min.y), abs(center.y-index.max.y))
+	var results []Result
+	collect := func(e entry) {
+		results = append(results, Result{Actor: e.actor, Position: e.position, Distance: point.Distance(e.position)})
+	}
+	inY := func(y int) bool { return index.min.y <= y && y <= index.max.y }
+	inX := func(x int) bool { return index.min.x <= x && x <= index.max.x }
+	for ring := first; ring <= last; ring++ {
+		lo, hi := cell{center.x - ring, center.y - ring}, cell{center.x + ring, center.y + ring}
+		for x := max(lo.x, index.min.x); x <= min(hi.x, index.max.x); x++ {
+			if inY(lo.y) {
+				index.visitCell(cell{x, lo.y}, filters, collect)
+			}
+			if ring > 0 && inY(hi.y) {
+				index.visitCell(cell{x, hi.y}, filters, collect)
+			}
+		}
+		for y := max(lo.y+1, index.min.y); y <= min(hi.y-1, index.max.y); y++ {
+			if inX(lo.x) {
+				index.visitCell(cell{lo.x, y}, filters, collect)
+			}
+			if inX(hi.x) {
+				index.visitCell(cell{hi.x, y}, filters, collect)
+			}
+		}
+
+		sortByDistance(results)
+		if len(results) >= k && results[k-1].Distance <= float64(ring)*CellSize {
+			break
+		}
+	}
+	return results[:min(k, len(results))]
+}
+
+func sortByDistance(results []Result) {
+	slices.SortStableFunc(results, func(a, b Result) int { return cmp.Compare(a.Distance, b.Distance) })
+}
+
+func abs(n int) int {
+	return max(n, -n)
+}
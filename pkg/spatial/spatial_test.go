@@ -0,0 +1,78 @@
+package spatial_test
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/Maurits825/satisfactory-savefile-parser/internal/testsave"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/geometry"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/model"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/saveformat"
+	"github.com/Maurits825/satisfactory-savefile-parser/pkg/spatial"
+)
+
+func position(actor *saveformat.Actor) geometry.Vector {
+	return geometry.ActorTransform(actor.Header).Position()
+}
+
+func actors(results []spatial.Result) []*saveformat.Actor {
+	found := make([]*saveformat.Actor, 0, len(results))
+	for _, result := range results {
+		found = append(found, result.Actor)
+	}
+	return found
+}
+
+func TestIndex(t *testing.T) {
+	save := testsave.Load(t)
+	index := spatial.NewIndex(save)
+
+	var nodes []*saveformat.Actor
+	for _, actor := range save.Actors {
+		if model.ClassName(actor.Header.TypePath) == "BP_ResourceNode_C" {
+			nodes = append(nodes, actor)
+		}
+	}
+	if len(nodes) != 459 || index.Len() < len(nodes) {
+		t.Fatal("Indexed:", index.Len(), "nodes:", len(nodes))
+	}
+	isNode := spatial.Classes("BP_ResourceNode_C")
+
+	// compare with a scan of all nodes
+	for _, point := range []geometry.Vector{position(nodes[0]), position(nodes[200]), {X: -300000, Y: 300000}, {X: 1e7}, {X: 1e9, Y: -1e9}} {
+		want := slices.Clone(nodes)
+		slices.SortStableFunc(want, func(a, b *saveformat.Actor) int {
+			return cmp.Compare(point.Distance(position(a)), point.Distance(position(b)))
+		})
+
+		if got := actors(index.Nearest(point, 5, isNode)); !slices.Equal(got, want[:5]) {
+			t.Error("Nearest of", point, "is", len(got), "actors")
+		}
+
+		radius := point.Distance(position(want[20]))
+		var inRadius []*saveformat.Actor
+		for _, node := range want {
+			if point.Distance(position(node)) <= radius {
+				inRadius = append(inRadius, node)
+			}
+		}
+		if got := actors(index.InRadius(point, radius, isNode)); !slices.Equal(got, inRadius) {
+			t.Error("In radius of", point, "got", len(got), "want", len(inRadius))
+		}
+	}
+
+	box := geometry.AABB{Min: geometry.Vector{X: -100000, Y: -100000, Z: -1e6}, Max: geometry.Vector{X: 100000, Y: 100000, Z: 1e6}}
+	var within []*saveformat.Actor
+	for _, node := range nodes {
+		if box.Contains(position(node)) {
+			within = append(within, node)
+		}
+	}
+	if got := actors(index.Within(box, isNode)); len(within) == 0 || !slices.Equal(got, within) {
+		t.Error("Within got", len(got), "want", len(within))
+	}
+	if got := index.Within(box, isNode, spatial.ClassPrefix("Build_")); len(got) != 0 {
+		t.Error("Filters must all match:", len(got))
+	}
+}